@@ -0,0 +1,43 @@
+package caskin
+
+// Access an action on an object
+type Access struct {
+	Object Object
+	Action Action
+}
+
+// AccessComparison the accesses only a has, only b has and both have
+type AccessComparison struct {
+	OnlyA  []*Access
+	OnlyB  []*Access
+	Shared []*Access
+}
+
+// compareAccess compare the accesses by object's id and action, keeping the order of a and b
+func compareAccess(a, b []*Access) *AccessComparison {
+	key := func(v *Access) [2]interface{} {
+		return [2]interface{}{v.Object.GetID(), v.Action}
+	}
+	inA, inB := map[[2]interface{}]bool{}, map[[2]interface{}]bool{}
+	for _, v := range a {
+		inA[key(v)] = true
+	}
+	for _, v := range b {
+		inB[key(v)] = true
+	}
+
+	c := &AccessComparison{}
+	for _, v := range a {
+		if inB[key(v)] {
+			c.Shared = append(c.Shared, v)
+		} else {
+			c.OnlyA = append(c.OnlyA, v)
+		}
+	}
+	for _, v := range b {
+		if !inA[key(v)] {
+			c.OnlyB = append(c.OnlyB, v)
+		}
+	}
+	return c
+}
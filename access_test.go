@@ -0,0 +1,110 @@
+package caskin_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+type accessExecutor interface {
+	CompareUserAccess(a, b caskin.User) (*caskin.AccessComparison, error)
+	CompareRoleAccess(a, b caskin.Role) (*caskin.AccessComparison, error)
+	CopyAccess(a, b caskin.User) error
+}
+
+// accessString such as "[object_1 write] [] [object_1 read]" of only a, only b and shared accesses
+func accessString(c *caskin.AccessComparison) string {
+	var out []interface{}
+	for _, accesses := range [][]*caskin.Access{c.OnlyA, c.OnlyB, c.Shared} {
+		var s []string
+		for _, v := range accesses {
+			s = append(s, fmt.Sprintf("%v %v", v.Object.Encode(), v.Action))
+		}
+		out = append(out, s)
+	}
+	return fmt.Sprintf("%v %v %v", out...)
+}
+
+func TestCompareUserAccess(t *testing.T) {
+	s := newStage(t, nil)
+	s.assign(t, 3, 1)
+	s.assign(t, 4, 2)
+	c, err := s.executor(s.as(1)).(accessExecutor).CompareUserAccess(&example.User{ID: 3}, &example.User{ID: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// admin inherits member, and data is under root
+	if got := accessString(c); got != "[object_1 write object_2 write] [] [object_1 read object_2 read]" {
+		t.Fatal(got)
+	}
+
+	if _, err := s.executor(s.as(1)).(accessExecutor).CompareUserAccess(&example.User{ID: 3}, &example.User{ID: 9}); !errors.Is(err, caskin.ErrNotExists) {
+		t.Fatal(err)
+	}
+}
+
+func TestCompareUserAccessReadable(t *testing.T) {
+	s := newReadableStage(t)
+	s.e.AddPolicy("role_1", s.domain.Encode(), "object_2", string(caskin.Read))
+	c, err := s.executor(s.as(1)).(accessExecutor).CompareUserAccess(&example.User{ID: 3}, &example.User{ID: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := accessString(c); got != "[object_1 write object_2 read] [] [object_1 read]" {
+		t.Fatal(got)
+	}
+
+	// user 4 can't read data, its accesses are not compared
+	c, err = s.executor(s.as(4)).(accessExecutor).CompareUserAccess(&example.User{ID: 3}, &example.User{ID: 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := accessString(c); got != "[object_1 write] [] [object_1 read]" {
+		t.Fatal(got)
+	}
+}
+
+func TestCompareRoleAccess(t *testing.T) {
+	s, _ := newCrossDomainStage(t)
+	c, err := s.executor(s.as(1)).(accessExecutor).CompareRoleAccess(&example.Role{ID: 1}, &example.Role{ID: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// only the roles' own policies are compared, without inheritance
+	if got := accessString(c); got != "[object_1 write] [object_1 read] []" {
+		t.Fatal(got)
+	}
+
+	if _, err := s.executor(s.as(1)).(accessExecutor).CompareRoleAccess(&example.Role{ID: 1}, &example.Role{ID: 3}); !errors.Is(err, caskin.ErrCrossDomain) {
+		t.Fatal(err)
+	}
+	var perr *caskin.PermissionError
+	if _, err := s.executor(s.as(4)).(accessExecutor).CompareRoleAccess(&example.Role{ID: 1}, &example.Role{ID: 2}); !errors.As(err, &perr) {
+		t.Fatal(err)
+	}
+}
+
+func TestCopyAccess(t *testing.T) {
+	s := newReadableStage(t)
+	// user 4 can't write admin of user 3
+	var perr *caskin.PermissionError
+	if err := s.executor(s.as(4)).(accessExecutor).CopyAccess(&example.User{ID: 2}, &example.User{ID: 3}); !errors.As(err, &perr) {
+		t.Fatal(err)
+	}
+	if roles, _ := s.e.GetRolesForUser("user_2", s.domain.Encode()); len(roles) != 0 {
+		t.Fatal(roles)
+	}
+
+	if err := s.executor(s.as(1)).(accessExecutor).CopyAccess(&example.User{ID: 4}, &example.User{ID: 3}); err != nil {
+		t.Fatal(err)
+	}
+	// user 4 keeps its own role
+	for _, role := range []string{"role_1", "role_2"} {
+		if ok, _ := s.e.HasRoleForUser("user_4", role, s.domain.Encode()); !ok {
+			t.Fatal(role)
+		}
+	}
+}
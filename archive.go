@@ -0,0 +1,247 @@
+package caskin
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/ahmetb/go-linq/v3"
+)
+
+// DomainArchive the self-contained snapshot of a domain,
+// roles, objects and users are referred by their id in the source domain
+type DomainArchive struct {
+	Domain Domain
+	*DomainSpec
+	// roles which have domain admin role's g
+	DomainAdminRoles []Role
+	// user to roles 's g
+	RolesForUsers []*RolesForUser
+}
+
+type domainArchiveJSON struct {
+	Domain json.RawMessage `json:"domain"`
+	*domainSpecJSON
+	DomainAdminRoles []uint64            `json:"domain_admin_roles"`
+	RolesForUsers    []*rolesForUserJSON `json:"roles_for_users"`
+}
+
+type rolesForUserJSON struct {
+	User  uint64   `json:"user"`
+	Roles []uint64 `json:"roles"`
+}
+
+// EncodeDomainArchive encode DomainArchive to JSON data
+func EncodeDomainArchive(archive *DomainArchive) ([]byte, error) {
+	spec, err := newDomainSpecJSON(archive.DomainSpec)
+	if err != nil {
+		return nil, err
+	}
+
+	domain, err := json.Marshal(archive.Domain)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := &domainArchiveJSON{
+		Domain:           domain,
+		domainSpecJSON:   spec,
+		DomainAdminRoles: getIDList(archive.DomainAdminRoles),
+	}
+	for _, v := range archive.RolesForUsers {
+		raw.RolesForUsers = append(raw.RolesForUsers, &rolesForUserJSON{
+			User:  v.User.GetID(),
+			Roles: getIDList(v.Roles),
+		})
+	}
+
+	return json.MarshalIndent(raw, "", "  ")
+}
+
+// DecodeDomainArchive decode JSON data to DomainArchive by the EntryFactory
+func DecodeDomainArchive(data []byte, factory EntryFactory) (*DomainArchive, error) {
+	raw := &domainArchiveJSON{domainSpecJSON: &domainSpecJSON{}}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return nil, err
+	}
+
+	spec, err := raw.toSpec(factory)
+	if err != nil {
+		return nil, err
+	}
+
+	domain := factory.NewDomain()
+	if err := json.Unmarshal(raw.Domain, domain); err != nil {
+		return nil, err
+	}
+
+	archive := &DomainArchive{
+		Domain:     domain,
+		DomainSpec: spec,
+	}
+	for _, v := range raw.DomainAdminRoles {
+		role := factory.NewRole()
+		role.SetID(v)
+		archive.DomainAdminRoles = append(archive.DomainAdminRoles, role)
+	}
+	for _, v := range raw.RolesForUsers {
+		ru := &RolesForUser{User: factory.NewUser()}
+		ru.User.SetID(v.User)
+		for _, id := range v.Roles {
+			role := factory.NewRole()
+			role.SetID(id)
+			ru.Roles = append(ru.Roles, role)
+		}
+		archive.RolesForUsers = append(archive.RolesForUsers, ru)
+	}
+
+	return archive, nil
+}
+
+// prune keep only the roles and objects, and the g, g2, p which refer to them
+// 1. drop roles and objects whose object code refers to a dropped object
+// 2. clear the parent of kept roles and objects whose parent is dropped
+// 3. drop p, domain admin role's g and user to roles 's g which refer to dropped roles and objects
+func (a *DomainArchive) prune(roles []Role, objects []Object) {
+	for n := -1; n != len(objects); {
+		n = len(objects)
+		objects = keepByObject(objects, objects).([]Object)
+	}
+	roles = keepByObject(roles, objects).([]Role)
+
+	rm, om := getIDMap(roles), getIDMap(objects)
+	for _, v := range roles {
+		if _, ok := rm[v.GetParentID()]; !ok {
+			v.SetParentID(0)
+		}
+	}
+	for _, v := range objects {
+		if _, ok := om[v.GetParentID()]; !ok {
+			v.SetParentID(0)
+		}
+	}
+
+	var policies []*Policy
+	for _, v := range a.Policies {
+		_, ok1 := rm[v.Role.GetID()]
+		_, ok2 := om[v.Object.GetID()]
+		if ok1 && ok2 {
+			policies = append(policies, v)
+		}
+	}
+
+	var admins []Role
+	for _, v := range a.DomainAdminRoles {
+		if _, ok := rm[v.GetID()]; ok {
+			admins = append(admins, v)
+		}
+	}
+
+	var rus []*RolesForUser
+	for _, v := range a.RolesForUsers {
+		ru := &RolesForUser{User: v.User}
+		for _, r := range v.Roles {
+			if _, ok := rm[r.GetID()]; ok {
+				ru.Roles = append(ru.Roles, r)
+			}
+		}
+		if len(ru.Roles) != 0 {
+			rus = append(rus, ru)
+		}
+	}
+
+	a.Roles, a.Objects, a.Policies = roles, objects, policies
+	a.DomainAdminRoles, a.RolesForUsers = admins, rus
+}
+
+// keepByObject keep the entries of source without object code or whose object code refers to one of objects
+func keepByObject(source interface{}, objects []Object) interface{} {
+	codes := map[string]bool{}
+	for _, v := range objects {
+		codes[v.Encode()] = true
+	}
+
+	out := reflect.New(reflect.TypeOf(source))
+	linq.From(source).Where(func(v interface{}) bool {
+		object := v.(entry).GetObject()
+		return object == "" || codes[object]
+	}).ToSlice(out.Interface())
+	return out.Elem().Interface()
+}
+
+// DomainImport the result of importing a DomainArchive
+type DomainImport struct {
+	// source role id to target role id
+	RoleID map[uint64]uint64
+	// source object id to target object id
+	ObjectID map[uint64]uint64
+	// users not in metadata database whose g are not restored
+	MissingUsers []uint64
+}
+
+// validate check the archive's spec, and the object code of roles and objects refers to the archive's objects
+func (a *DomainArchive) validate() error {
+	if err := a.DomainSpec.validate(); err != nil {
+		return err
+	}
+
+	codes := map[string]bool{}
+	for _, v := range a.Objects {
+		codes[v.Encode()] = true
+	}
+	var entries []entry
+	for _, v := range a.Roles {
+		entries = append(entries, v)
+	}
+	for _, v := range a.Objects {
+		entries = append(entries, v)
+	}
+	for _, v := range entries {
+		if v.GetObject() != "" && !codes[v.GetObject()] {
+			return fmt.Errorf("%w: %v's object %v is not in archive", ErrInvalidDomainSpec, v.Encode(), v.GetObject())
+		}
+	}
+
+	return nil
+}
+
+// checkInDomain the archive's roles and objects are all in the domain, it is ErrCrossDomain if not
+func (a *DomainArchive) checkInDomain(domain Domain) error {
+	for _, v := range a.Roles {
+		if err := isInDomain(v, domain); err != nil {
+			return err
+		}
+	}
+	for _, v := range a.Objects {
+		if err := isInDomain(v, domain); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyEntry copy source to target by JSON, and set target's object code by the source's one in codes
+func copyEntry(source, target entry, codes map[string]string) error {
+	data, err := json.Marshal(source)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return err
+	}
+
+	if source.GetObject() == "" {
+		return nil
+	}
+	code, ok := codes[source.GetObject()]
+	if !ok {
+		return fmt.Errorf("%w: %v's object %v is not in archive", ErrInvalidDomainSpec, source.Encode(), source.GetObject())
+	}
+	setter, ok := target.(ObjectSetter)
+	if !ok {
+		return fmt.Errorf("%w: %T can't set object code", ErrInvalidEntryType, target)
+	}
+	setter.SetObject(code)
+
+	return nil
+}
@@ -0,0 +1,170 @@
+package caskin_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+type archiveExecutor interface {
+	ExportDomain(caskin.Domain) (*caskin.DomainArchive, error)
+	ImportDomain(*caskin.DomainArchive, caskin.Domain) (*caskin.DomainImport, error)
+}
+
+func TestDomainArchive(t *testing.T) {
+	s := newStage(t, nil)
+	s.assign(t, 2, 2)
+	if err := s.db.Model(&example.Role{ID: 1}).Update("object", "object_1").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := s.executor(s.as(1)).(archiveExecutor).ExportDomain(&example.Domain{ID: s.domain.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := caskin.EncodeDomainArchive(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive, err = caskin.DecodeDomainArchive(data, example.NewEntryFactory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archive.Roles) != 2 || len(archive.Objects) != 2 || len(archive.Policies) != 2 || len(archive.RolesForUsers) != 1 {
+		t.Fatalf("%s", data)
+	}
+
+	target := &example.Domain{Name: "domain_2"}
+	if err := s.db.Create(target).Error; err != nil {
+		t.Fatal(err)
+	}
+	superadmin := &provider{user: &example.User{ID: 1}, domain: target}
+	result, err := s.executor(superadmin).(archiveExecutor).ImportDomain(archive, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result == nil || len(result.RoleID) != 2 || len(result.ObjectID) != 2 || len(result.MissingUsers) != 0 {
+		t.Fatal(result)
+	}
+
+	admin := &example.Role{ID: result.RoleID[1]}
+	root := &example.Object{ID: result.ObjectID[1]}
+	if err := s.mdb.TakeRole(admin); err != nil || admin.DomainID != target.ID || admin.Object != root.Encode() {
+		t.Fatal(admin, err)
+	}
+	member := &example.Role{ID: result.RoleID[2]}
+	data2 := &example.Object{ID: result.ObjectID[2]}
+	if ok, _ := s.e.HasRoleForUser(admin.Encode(), member.Encode(), target.Encode()); !ok {
+		t.Fatal("role's g is not restored")
+	}
+	if !s.e.HasNamedGroupingPolicy("g2", data2.Encode(), root.Encode(), target.Encode()) {
+		t.Fatal("object's g2 is not restored")
+	}
+	if !s.e.HasPolicy(admin.Encode(), target.Encode(), root.Encode(), string(caskin.Write)) {
+		t.Fatal("p is not restored")
+	}
+	if ok, _ := s.e.HasRoleForUser("user_2", member.Encode(), target.Encode()); !ok {
+		t.Fatal("user's g is not restored")
+	}
+}
+
+func TestDomainArchiveObjectCode(t *testing.T) {
+	s := newStage(t, nil)
+	archive, err := s.executor(s.as(1)).(archiveExecutor).ExportDomain(&example.Domain{ID: s.domain.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	archive.Roles[0].(*example.Role).Object = "object_9"
+	if _, err := s.executor(s.as(1)).(archiveExecutor).ImportDomain(archive, s.domain); !errors.Is(err, caskin.ErrInvalidDomainSpec) {
+		t.Fatal(err)
+	}
+}
+
+func TestExportDomainPermission(t *testing.T) {
+	s := newStage(t, nil)
+	s.assign(t, 2, 2)
+	// user 2 as member reads root and data, but data's object is secret which can't be read,
+	// so data is dropped, and member is dropped as its object is data
+	if err := s.db.Create(&example.Object{Name: "secret", Type: example.ObjectTypeDefault, Object: "object_3", DomainID: s.domain.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []interface{}{
+		&example.Object{ID: 1, Object: "object_1"},
+		&example.Object{ID: 2, Object: "object_3"},
+		&example.Role{ID: 1, Object: "object_1"},
+		&example.Role{ID: 2, Object: "object_2"},
+	} {
+		if err := s.db.Updates(v).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	archive, err := s.executor(s.as(2)).(archiveExecutor).ExportDomain(&example.Domain{ID: s.domain.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archive.Roles) != 1 || archive.Roles[0].GetID() != 1 || len(archive.Objects) != 1 || archive.Objects[0].GetID() != 1 {
+		t.Fatal(archive.Roles, archive.Objects)
+	}
+	if len(archive.Policies) != 1 || len(archive.RolesForUsers) != 0 {
+		t.Fatal(archive.Policies, archive.RolesForUsers)
+	}
+
+	other := &example.Domain{Name: "domain_2"}
+	if err := s.db.Create(other).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.executor(s.as(2)).(archiveExecutor).ExportDomain(other); !errors.Is(err, caskin.ErrCrossDomain) {
+		t.Fatal(err)
+	}
+}
+
+func TestImportDomainPermission(t *testing.T) {
+	s := newStage(t, &caskin.Option{DomainAdminOption: &caskin.DomainAdminOption{Enable: true, Role: pickAdmin}})
+	// user 2 is domain admin as admin, user 3 is member who writes nothing
+	s.assign(t, 2, 1)
+	s.assign(t, 3, 2)
+	archive, err := s.executor(s.as(1)).(archiveExecutor).ExportDomain(s.domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archive.DomainAdminRoles) != 1 {
+		t.Fatal(archive.DomainAdminRoles)
+	}
+
+	// the archive's roles and objects conflict with the existing ones instead of being upserted onto them
+	if _, err := s.executor(s.as(1)).(archiveExecutor).ImportDomain(archive, s.domain); err == nil {
+		t.Fatal("archive is imported onto the existing roles and objects")
+	}
+	for _, v := range archive.Roles {
+		v.(*example.Role).Name += "_copy"
+	}
+	for _, v := range archive.Objects {
+		v.(*example.Object).Name += "_copy"
+	}
+
+	if _, err := s.executor(s.as(3)).(archiveExecutor).ImportDomain(archive, s.domain); !errors.Is(err, caskin.ErrIsNotSuperAdmin) {
+		t.Fatal(err)
+	}
+	archive.DomainAdminRoles = nil
+	if _, err := s.executor(s.as(3)).(archiveExecutor).ImportDomain(archive, s.domain); !errors.Is(err, caskin.ErrNoWritePermission) {
+		t.Fatal(err)
+	}
+	if roles, _ := s.mdb.GetRoleInDomain(s.domain); len(roles) != 2 {
+		t.Fatal("roles are created without permission", roles)
+	}
+	if objects, _ := s.mdb.GetObjectInDomain(s.domain); len(objects) != 2 {
+		t.Fatal("objects are created without permission", objects)
+	}
+
+	// domain admin could import the roles and objects, but not the domain admin roles
+	result, err := s.executor(s.as(2)).(archiveExecutor).ImportDomain(archive, s.domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.RoleID) != 2 || len(result.ObjectID) != 2 {
+		t.Fatal(result)
+	}
+}
@@ -0,0 +1,45 @@
+package caskin
+
+import "fmt"
+
+// Enforcer enforce the user's action on the object in the domain,
+// the enforcer built by NewEnforcer implements it
+type Enforcer interface {
+	Enforce(User, Object, Domain, Action) (bool, error)
+}
+
+// Unmapped how an authorizer such as caskinhttp's and caskingrpc's deals with the request of no registered route
+type Unmapped string
+
+const (
+	// UnmappedAllow pass the request without authentication
+	UnmappedAllow Unmapped = "allow"
+	// UnmappedDeny reject the request with ErrForbidden
+	UnmappedDeny Unmapped = "deny"
+)
+
+// Permission the object and action required by a registered route
+type Permission struct {
+	Object Object
+	Action Action
+}
+
+// Authorize if current user could do the permission's action on its object in current domain
+// 1. it is ErrUnauthorized if there is no current user and domain
+// 2. it is PermissionError if the action is not allowed
+func (p *Permission) Authorize(enforcer Enforcer, provider CurrentUserProvider) error {
+	user, domain, err := provider.Get()
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnauthorized, err)
+	}
+
+	ok, err := enforcer.Enforce(user, p.Object, domain, p.Action)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return &PermissionError{User: user, Domain: domain, Entry: p.Object.Encode(), Action: p.Action}
+	}
+
+	return nil
+}
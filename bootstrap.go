@@ -0,0 +1,82 @@
+package caskin
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/casbin/casbin/v2"
+)
+
+// superadminMatcher the matcher clause of superadmin after casbin escapes "r.sub" to "r_sub"
+var superadminMatcher = regexp.MustCompile(fmt.Sprintf(`g\(\s*r_sub\s*,\s*"%v"\s*,\s*"%v"\s*\)`,
+	regexp.QuoteMeta(SuperadminRole), regexp.QuoteMeta(SuperadminDomain)))
+
+// bootstrap it is reentrant to prepare superadmin when superadmin is enabled
+// 1. check casbin model's matcher has the superadmin clause
+// 2. ensure superadmin domain and role exist in metadata database if RealSuperadminInDB
+// 3. add initial users as superadmin role in superadmin domain
+func (m *management) bootstrap(e casbin.IEnforcer) error {
+	if !m.option.IsEnableSuperAdmin() {
+		return nil
+	}
+
+	if err := checkSuperadminMatcher(e); err != nil {
+		return err
+	}
+
+	domain := m.option.GetSuperAdminDomain()
+	role := m.option.GetSuperAdminRole()
+	if domain.Encode() != SuperadminDomain || role.Encode() != SuperadminRole {
+		return fmt.Errorf("%w: superadmin role and domain should be encoded as %q and %q",
+			ErrInvalidSuperadmin, SuperadminRole, SuperadminDomain)
+	}
+
+	if m.option.SuperAdminOption.RealSuperadminInDB {
+		if err := m.bootstrapSuperadminInDB(domain, role); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range m.option.SuperAdminOption.InitialUsers {
+		user := m.factory.NewUser()
+		user.SetID(id)
+		if err := m.mdb.TakeUser(user); err != nil {
+			return fmt.Errorf("%w: initial superadmin user %v %v", ErrNotExists, id, err)
+		}
+		if err := m.e.AddRoleForUserInDomain(user, role, domain); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *management) bootstrapSuperadminInDB(domain Domain, role Role) error {
+	if m.option.SuperAdminOption.Domain == nil || m.option.SuperAdminOption.Role == nil {
+		return fmt.Errorf("%w: superadmin role and domain should be provided to keep them in metadata database",
+			ErrInvalidSuperadmin)
+	}
+
+	if err := m.mdb.TakeDomain(domain); err != nil {
+		if err := m.mdb.CreateDomain(domain); err != nil {
+			return err
+		}
+	}
+
+	role.SetDomainID(domain.GetID())
+	if err := m.mdb.TakeRole(role); err != nil {
+		return m.mdb.CreateRole(role)
+	}
+
+	return nil
+}
+
+func checkSuperadminMatcher(e casbin.IEnforcer) error {
+	assertion, ok := e.GetModel()["m"]["m"]
+	if !ok || !superadminMatcher.MatchString(assertion.Value) {
+		return fmt.Errorf("%w: casbin model's matcher lacks superadmin clause `g(r.sub, %q, %q)`",
+			ErrInvalidSuperadmin, SuperadminRole, SuperadminDomain)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package caskin_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+)
+
+func (r *superadminRole) TableName() string {
+	return "roles"
+}
+
+type superadminDomain struct {
+	example.Domain
+}
+
+func (d *superadminDomain) TableName() string {
+	return "domains"
+}
+
+func (d *superadminDomain) Encode() string {
+	if d.ID == caskin.DefaultSuperadminDomainID {
+		return caskin.SuperadminDomain
+	}
+	return d.Domain.Encode()
+}
+
+func (d *superadminDomain) Decode(code string) error {
+	if code == caskin.SuperadminDomain {
+		d.ID = caskin.DefaultSuperadminDomainID
+		return nil
+	}
+	return d.Domain.Decode(code)
+}
+
+type realSuperadminFactory struct {
+	superadminFactory
+}
+
+func (f *realSuperadminFactory) NewDomain() caskin.Domain {
+	return &superadminDomain{}
+}
+
+func TestBootstrap(t *testing.T) {
+	s := newStage(t, nil)
+	if ok, _ := s.e.HasRoleForUser("user_1", caskin.SuperadminRole, caskin.SuperadminDomain); !ok {
+		t.Fatal("initial user is not superadmin")
+	}
+
+	option := &caskin.Option{SuperAdminOption: &caskin.SuperAdminOption{Enable: true, InitialUsers: []uint64{1, 2}}}
+	if _, err := caskin.New(s.mdb, s.e, example.NewEntryFactory(), option); err != nil {
+		t.Fatal(err)
+	}
+	if users := s.e.GetUsersForRoleInDomain(caskin.SuperadminRole, caskin.SuperadminDomain); len(users) != 2 {
+		t.Fatal(users)
+	}
+
+	option.SuperAdminOption.InitialUsers = []uint64{99}
+	if _, err := caskin.New(s.mdb, s.e, example.NewEntryFactory(), option); !errors.Is(err, caskin.ErrNotExists) {
+		t.Fatal(err)
+	}
+
+	option.SuperAdminOption.InitialUsers = nil
+	option.SuperAdminOption.RealSuperadminInDB = true
+	if _, err := caskin.New(s.mdb, s.e, example.NewEntryFactory(), option); !errors.Is(err, caskin.ErrInvalidSuperadmin) {
+		t.Fatal(err)
+	}
+}
+
+func TestBootstrapRealSuperadminInDB(t *testing.T) {
+	s := newStage(t, nil)
+	option := &caskin.Option{SuperAdminOption: &caskin.SuperAdminOption{
+		Enable:             true,
+		RealSuperadminInDB: true,
+		Role: func() caskin.Role {
+			return &superadminRole{example.Role{ID: caskin.DefaultSuperadminRoleID, Name: caskin.DefaultSuperadminRoleName}}
+		},
+		Domain: func() caskin.Domain {
+			return &superadminDomain{example.Domain{ID: caskin.DefaultSuperadminDomainID, Name: caskin.DefaultSuperadminDomainName}}
+		},
+		InitialUsers: []uint64{2},
+	}}
+	factory := &realSuperadminFactory{superadminFactory{example.NewEntryFactory()}}
+	for i := 0; i < 2; i++ {
+		if _, err := caskin.New(s.mdb, s.e, factory, option); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	domain := &example.Domain{ID: caskin.DefaultSuperadminDomainID}
+	if err := s.mdb.TakeDomain(domain); err != nil {
+		t.Fatal(err)
+	}
+	role := &example.Role{ID: caskin.DefaultSuperadminRoleID}
+	if err := s.mdb.TakeRole(role); err != nil || role.DomainID != domain.ID {
+		t.Fatal(role, err)
+	}
+	if ok, _ := s.e.HasRoleForUser("user_2", caskin.SuperadminRole, caskin.SuperadminDomain); !ok {
+		t.Fatal("initial user is not superadmin")
+	}
+}
+
+func TestBootstrapMatcher(t *testing.T) {
+	s := newStage(t, nil)
+	m, err := model.NewModelFromString(`
+[request_definition]
+r = sub, dom, obj, act
+
+[policy_definition]
+p = sub, dom, obj, act
+
+[role_definition]
+g = _, _, _
+g2 = _, _, _
+
+[policy_effect]
+e = some(where (p.eft == allow))
+
+[matchers]
+m = g(r.sub, p.sub, r.dom) && g2(r.obj, p.obj, r.dom) && r.dom == p.dom && r.act == p.act
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := casbin.NewEnforcer(m)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	option := &caskin.Option{SuperAdminOption: &caskin.SuperAdminOption{Enable: true}}
+	if _, err := caskin.New(s.mdb, e, example.NewEntryFactory(), option); !errors.Is(err, caskin.ErrInvalidSuperadmin) {
+		t.Fatal(err)
+	}
+	option.SuperAdminOption.Enable = false
+	if _, err := caskin.New(s.mdb, e, example.NewEntryFactory(), option); err != nil {
+		t.Fatal(err)
+	}
+}
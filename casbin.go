@@ -8,6 +8,7 @@ type ienforcer interface {
 	// check permission
 	Enforce(User, Object, Domain, Action) (bool, error)
 	IsSuperAdmin(User) (bool, error)
+	IsDomainAdmin(User, Domain) (bool, error)
 
 	// get grouping entry in domain
 	GetRolesForUserInDomain(User, Domain) []Role
@@ -29,6 +30,14 @@ type ienforcer interface {
 	AddRoleForUserInDomain(User, Role, Domain) error
 	RemoveRoleForUserInDomain(User, Role, Domain) error
 
+	// get or add domain admin role grouping information
+	GetDomainAdminRolesInDomain(Domain) []Role
+	AddDomainAdminRoleInDomain(Role, Domain) error
+
+	// add or remove role-parent grouping information
+	AddParentForRoleInDomain(Role, Role, Domain) error
+	RemoveParentForRoleInDomain(Role, Role, Domain) error
+
 	// add or remove object-parent grouping information
 	AddParentForObjectInDomain(Object, Object, Domain) error
 	RemoveParentForObjectInDomain(Object, Object, Domain) error
@@ -41,6 +50,18 @@ type ienforcer interface {
 
 	// remove entry in domain
 	RemoveUsersInDomain(Domain) error
+
+	// get, add or remove raw p, g, g2 rules
+	GetAllRules() []*Rule
+	GetRulesInDomain(Domain) []*Rule
+	AddRules([]*Rule) error
+	RemoveRules([]*Rule) error
+}
+
+// Rule the raw casbin rule, PType is p, g or g2
+type Rule struct {
+	PType  string   `json:"ptype"`
+	Values []string `json:"values"`
 }
 
 type enforcer struct {
@@ -49,13 +70,17 @@ type enforcer struct {
 }
 
 func (e *enforcer) Enforce(user User, object Object, domain Domain, action Action) (bool, error) {
-	return e.e.Enforce(user.Encode(), domain.Encode(), object.Encode(), action)
+	return e.e.Enforce(user.Encode(), domain.Encode(), object.Encode(), string(action))
 }
 
 func (e *enforcer) IsSuperAdmin(user User) (bool, error) {
 	return e.e.HasRoleForUser(user.Encode(), SuperadminRole, SuperadminDomain)
 }
 
+func (e *enforcer) IsDomainAdmin(user User, domain Domain) (bool, error) {
+	return e.e.GetRoleManager().HasLink(user.Encode(), DomainAdminRole, domain.Encode())
+}
+
 func (e *enforcer) GetRolesForUserInDomain(user User, domain Domain) []Role {
 	var roles []Role
 	rs := e.e.GetRolesForUserInDomain(user.Encode(), domain.Encode())
@@ -185,12 +210,12 @@ func (e *enforcer) RemoveObjectInDomain(object Object, domain Domain) error {
 }
 
 func (e *enforcer) AddPolicyInDomain(role Role, object Object, domain Domain, action Action) error {
-	_, err := e.e.AddPolicy(role.Encode(), domain.Encode(), object.Encode(), action)
+	_, err := e.e.AddPolicy(role.Encode(), domain.Encode(), object.Encode(), string(action))
 	return err
 }
 
 func (e *enforcer) RemovePolicyInDomain(role Role, object Object, domain Domain, action Action) error {
-	_, err := e.e.RemovePolicy(role.Encode(), domain.Encode(), object.Encode(), action)
+	_, err := e.e.RemovePolicy(role.Encode(), domain.Encode(), object.Encode(), string(action))
 	return err
 }
 
@@ -204,13 +229,41 @@ func (e *enforcer) RemoveRoleForUserInDomain(user User, role Role, domain Domain
 	return err
 }
 
+func (e *enforcer) GetDomainAdminRolesInDomain(domain Domain) []Role {
+	var roles []Role
+	rs := e.e.GetUsersForRoleInDomain(DomainAdminRole, domain.Encode())
+	for _, r := range rs {
+		role := e.factory.NewRole()
+		if err := role.Decode(r); err == nil {
+			roles = append(roles, role)
+		}
+	}
+
+	return roles
+}
+
+func (e *enforcer) AddDomainAdminRoleInDomain(role Role, domain Domain) error {
+	_, err := e.e.AddRoleForUserInDomain(role.Encode(), DomainAdminRole, domain.Encode())
+	return err
+}
+
+func (e *enforcer) AddParentForRoleInDomain(role1 Role, role2 Role, domain Domain) error {
+	_, err := e.e.AddRoleForUserInDomain(role2.Encode(), role1.Encode(), domain.Encode())
+	return err
+}
+
+func (e *enforcer) RemoveParentForRoleInDomain(role1 Role, role2 Role, domain Domain) error {
+	_, err := e.e.DeleteRoleForUserInDomain(role2.Encode(), role1.Encode(), domain.Encode())
+	return err
+}
+
 func (e *enforcer) AddParentForObjectInDomain(object1 Object, object2 Object, domain Domain) error {
-	_, err := e.e.AddNamedGroupingPolicy(ObjectPType, object1.Encode(), object2.Encode(), domain)
+	_, err := e.e.AddNamedGroupingPolicy(ObjectPType, object1.Encode(), object2.Encode(), domain.Encode())
 	return err
 }
 
 func (e *enforcer) RemoveParentForObjectInDomain(object1 Object, object2 Object, domain Domain) error {
-	_, err := e.e.RemoveNamedGroupingPolicy(ObjectPType, object1.Encode(), object2.Encode(), domain)
+	_, err := e.e.RemoveNamedGroupingPolicy(ObjectPType, object1.Encode(), object2.Encode(), domain.Encode())
 	return err
 }
 
@@ -305,6 +358,75 @@ func (e *enforcer) RemoveUsersInDomain(domain Domain) error {
 	return err
 }
 
+func (e *enforcer) GetAllRules() []*Rule {
+	var rules []*Rule
+	for _, v := range e.e.GetPolicy() {
+		rules = append(rules, &Rule{PType: PolicyPType, Values: v})
+	}
+	for _, v := range e.e.GetGroupingPolicy() {
+		rules = append(rules, &Rule{PType: RolePType, Values: v})
+	}
+	for _, v := range e.e.GetNamedGroupingPolicy(ObjectPType) {
+		rules = append(rules, &Rule{PType: ObjectPType, Values: v})
+	}
+
+	return rules
+}
+
+func (e *enforcer) GetRulesInDomain(domain Domain) []*Rule {
+	var rules []*Rule
+	for _, v := range e.e.GetFilteredPolicy(1, domain.Encode()) {
+		rules = append(rules, &Rule{PType: PolicyPType, Values: v})
+	}
+	for _, v := range e.e.GetFilteredGroupingPolicy(2, domain.Encode()) {
+		rules = append(rules, &Rule{PType: RolePType, Values: v})
+	}
+	for _, v := range e.e.GetFilteredNamedGroupingPolicy(ObjectPType, 2, domain.Encode()) {
+		rules = append(rules, &Rule{PType: ObjectPType, Values: v})
+	}
+
+	return rules
+}
+
+func (e *enforcer) AddRules(rules []*Rule) error {
+	for _, v := range rules {
+		var err error
+		if v.PType == PolicyPType {
+			_, err = e.e.AddNamedPolicy(v.PType, v.Values)
+		} else {
+			_, err = e.e.AddNamedGroupingPolicy(v.PType, v.Values)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *enforcer) RemoveRules(rules []*Rule) error {
+	m := map[string][][]string{}
+	for _, v := range rules {
+		m[v.PType] = append(m[v.PType], v.Values)
+	}
+
+	if len(m[PolicyPType]) != 0 {
+		if _, err := e.e.RemoveNamedPolicies(PolicyPType, m[PolicyPType]); err != nil {
+			return err
+		}
+	}
+	for _, ptype := range []string{RolePType, ObjectPType} {
+		if len(m[ptype]) == 0 {
+			continue
+		}
+		if _, err := e.e.RemoveNamedGroupingPolicies(ptype, m[ptype]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func NewEnforcer(e casbin.IEnforcer, factory EntryFactory) ienforcer {
 	return &enforcer{
 		e:       e,
@@ -1,12 +1,46 @@
 package caskin
 
+import "github.com/casbin/casbin/v2"
+
 type CurrentUserProvider interface {
 	Get() (User, Domain, error)
 }
 
 type management struct {
-	mdb MetaDB
-	e   ienforcer
+	mdb     MetaDB
+	e       ienforcer
+	factory EntryFactory
+	option  *Option
+}
+
+// New build caskin's management
+// 1. validate the option and the EntryFactory's codec
+// 2. wrap casbin's enforcer with the EntryFactory
+// 3. bootstrap superadmin if it is enabled
+func New(mdb MetaDB, e casbin.IEnforcer, factory EntryFactory, option *Option) (*management, error) {
+	if option == nil {
+		option = &Option{}
+	}
+
+	if err := option.validate(); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateEntryFactory(factory, option); err != nil {
+		return nil, err
+	}
+
+	m := &management{
+		mdb:     mdb,
+		e:       NewEnforcer(e, factory),
+		factory: factory,
+		option:  option,
+	}
+	if err := m.bootstrap(e); err != nil {
+		return nil, err
+	}
+
+	return m, nil
 }
 
 func (m *management) GetExecutor(provider CurrentUserProvider) *executor {
@@ -14,5 +48,7 @@ func (m *management) GetExecutor(provider CurrentUserProvider) *executor {
 		mdb:      m.mdb,
 		e:        m.e,
 		provider: provider,
+		factory:  m.factory,
+		option:   m.option,
 	}
 }
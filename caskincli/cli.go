@@ -0,0 +1,218 @@
+// Package caskincli is the command-line admin tool of caskin for operators.
+// entries are application specific, cmd/caskin is the binary for the example's models,
+// and an application builds its own `caskin` binary with its models:
+//
+//	func main() {
+//		caskincli.Main(&caskincli.Config{
+//			Factory:   model.NewEntryFactory(),
+//			Dialector: mysql.Open,
+//			MetaDB:    model.NewGormMDB,
+//		})
+//	}
+//
+// then run it such as
+//
+//	caskin -dsn "$DSN" -policy policy.csv -domain domain_1 roles
+//	caskin -dsn "$DSN" -policy policy.csv -domain domain_1 -as user_1 assign user_2 role_3
+//	caskin -dsn "$DSN" -policy snapshot.csv -domain domain_1 -as user_2 repl
+package caskincli
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/persist"
+	fileadapter "github.com/casbin/casbin/v2/persist/file-adapter"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Config the application's models and storages of the CLI
+type Config struct {
+	Factory caskin.EntryFactory
+	// Dialector open gorm's dialector of -dsn, such as mysql.Open
+	Dialector func(dsn string) gorm.Dialector
+	// MetaDB build caskin's MetaDB by gorm, such as NewGormMDB generated by caskin-gen -mdb
+	MetaDB func(*gorm.DB) caskin.MetaDB
+	// Adapter build casbin's adapter by gorm to keep policies in database,
+	// policies are in -policy file if it is nil
+	Adapter func(*gorm.DB) (persist.Adapter, error)
+	// Option caskin's option, such as superadmin and domain admin option
+	Option *caskin.Option
+}
+
+// Main run the CLI with os.Args and exit with 1 if it fails
+func Main(config *Config) {
+	if err := Run(config, os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "caskin:", err)
+		os.Exit(1)
+	}
+}
+
+// Run run the CLI with args, without the program name, and write the result to w
+func Run(config *Config, args []string, w io.Writer) error {
+	fs := flag.NewFlagSet("caskin", flag.ContinueOnError)
+	fs.SetOutput(w)
+	dsn := fs.String("dsn", "", "data source name of the metadata database")
+	model := fs.String("model", "configs/casbin_model.conf", "casbin model file")
+	policy := fs.String("policy", "", "casbin policy CSV file, unless the database adapter is configured")
+	as := fs.String("as", "", "code of the operator user, whose permissions are checked by caskin's executor")
+	domain := fs.String("domain", "", "code of the domain")
+	output := fs.String("output", "table", "output format, table or json")
+	fs.Usage = func() {
+		fmt.Fprintln(w, "usage: caskin [flags] <command> [args]")
+		fmt.Fprintln(w, "\ncommands:")
+		for _, v := range commands {
+			fmt.Fprintf(w, "  %-40v %v\n", v.usage, v.help)
+		}
+		fmt.Fprintln(w, "\nflags:")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return ErrUsage
+	}
+
+	cmd, ok := findCommand(fs.Arg(0))
+	if !ok {
+		fs.Usage()
+		return fmt.Errorf("%w: unknown command %v", ErrUsage, fs.Arg(0))
+	}
+	if *output != outputTable && *output != outputJSON {
+		return fmt.Errorf("%w: unknown output %v", ErrUsage, *output)
+	}
+
+	c, err := newCLI(config, *dsn, *model, *policy)
+	if err != nil {
+		return err
+	}
+	c.output = *output
+	c.w = w
+	if c.operator, err = c.decodeUser(*as, "-as"); err != nil && cmd.operator {
+		return err
+	}
+	if c.domain, err = c.decodeDomain(*domain, "-domain"); err != nil && cmd.domain {
+		return err
+	}
+
+	if err := cmd.run(c, fs.Args()[1:]); err != nil {
+		return err
+	}
+	if cmd.save {
+		return c.casbin.SavePolicy()
+	}
+	return nil
+}
+
+// cli the opened storages and global flags of a run
+type cli struct {
+	factory  caskin.EntryFactory
+	mdb      caskin.MetaDB
+	casbin   casbin.IEnforcer
+	executor func(caskin.CurrentUserProvider) executor
+	operator caskin.User
+	domain   caskin.Domain
+	output   string
+	w        io.Writer
+}
+
+// executor the operations of caskin's executor used by the CLI, caskin's executor built by GetExecutor implements it
+type executor interface {
+	ModifyUsersForRole(*caskin.UsersForRole) error
+	AddSuperadminUser(caskin.User) error
+	DeleteSuperadminUser(caskin.User) error
+	GetAllSuperadminUser() ([]caskin.User, error)
+	ExportDomain(caskin.Domain) (*caskin.DomainArchive, error)
+	ImportDomain(*caskin.DomainArchive, caskin.Domain) (*caskin.DomainImport, error)
+	VerifyConsistency() (*caskin.ConsistencyReport, error)
+	RepairConsistency(bool) (*caskin.ConsistencyReport, error)
+	ExportGraph(caskin.Domain, *caskin.GraphOption) (*caskin.Graph, error)
+	GetPermissionMatrix(caskin.Domain) (*caskin.PermissionMatrix, error)
+	CompareUserAccess(a, b caskin.User) (*caskin.AccessComparison, error)
+	CompareRoleAccess(a, b caskin.Role) (*caskin.AccessComparison, error)
+	CopyAccess(a, b caskin.User) error
+}
+
+func newCLI(config *Config, dsn, model, policy string) (*cli, error) {
+	if dsn == "" {
+		return nil, fmt.Errorf("%w: -dsn is required", ErrUsage)
+	}
+	db, err := gorm.Open(config.Dialector(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return nil, err
+	}
+
+	var adapter persist.Adapter
+	switch {
+	case config.Adapter != nil:
+		if adapter, err = config.Adapter(db); err != nil {
+			return nil, err
+		}
+	case policy != "":
+		adapter = fileadapter.NewAdapter(policy)
+	default:
+		return nil, fmt.Errorf("%w: -policy is required", ErrUsage)
+	}
+
+	e, err := casbin.NewEnforcer(model, adapter)
+	if err != nil {
+		return nil, err
+	}
+
+	mdb := config.MetaDB(db)
+	m, err := caskin.New(mdb, e, config.Factory, config.Option)
+	if err != nil {
+		return nil, err
+	}
+
+	return &cli{
+		factory: config.Factory,
+		mdb:     mdb,
+		casbin:  e,
+		executor: func(provider caskin.CurrentUserProvider) executor {
+			return m.GetExecutor(provider)
+		},
+	}, nil
+}
+
+// Get provide the operator and the domain as current user and current domain
+func (c *cli) Get() (caskin.User, caskin.Domain, error) {
+	return c.operator, c.domain, nil
+}
+
+func (c *cli) decodeUser(code, name string) (caskin.User, error) {
+	user := c.factory.NewUser()
+	return user, decode(user, code, name)
+}
+
+func (c *cli) decodeRole(code, name string) (caskin.Role, error) {
+	role := c.factory.NewRole()
+	return role, decode(role, code, name)
+}
+
+func (c *cli) decodeObject(code, name string) (caskin.Object, error) {
+	object := c.factory.NewObject()
+	return object, decode(object, code, name)
+}
+
+func (c *cli) decodeDomain(code, name string) (caskin.Domain, error) {
+	domain := c.factory.NewDomain()
+	return domain, decode(domain, code, name)
+}
+
+func decode(one interface{ Decode(string) error }, code, name string) error {
+	if code == "" {
+		return fmt.Errorf("%w: %v is required", ErrUsage, name)
+	}
+	if err := one.Decode(code); err != nil {
+		return fmt.Errorf("%w: %v: %v", ErrUsage, name, err)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package caskincli_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/caskincli"
+	"github.com/awatercolorpen/caskin/example"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// stagePolicy admin writes root and member reads root in domain 1, data is under root,
+// user 1 is superadmin, user 2 is admin, and user 3 is of role 9 which is not in metadata database,
+// saving the policies drops the comment line
+const stagePolicy = `# caskincli stage
+p, role_1, domain_1, object_1, write
+p, role_2, domain_1, object_1, read
+g, user_1, superadmin, superdomain
+g, user_2, role_1, domain_1
+g, user_3, role_9, domain_1
+g2, object_2, object_1, domain_1
+`
+
+// stage the example's entries in a sqlite file and the policies in a CSV file, both of them are reopened by every run
+type stage struct {
+	config *caskincli.Config
+	dsn    string
+	policy string
+}
+
+func newStage(t *testing.T) *stage {
+	dir := t.TempDir()
+	s := &stage{
+		config: &caskincli.Config{
+			Factory:   example.NewEntryFactory(),
+			Dialector: sqlite.Open,
+			MetaDB:    example.NewGormMDBByDB,
+			Option:    &caskin.Option{SuperAdminOption: &caskin.SuperAdminOption{Enable: true}},
+		},
+		dsn:    filepath.Join(dir, "caskin.db"),
+		policy: filepath.Join(dir, "policy.csv"),
+	}
+
+	db, err := gorm.Open(sqlite.Open(s.dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&example.User{}, &example.Role{}, &example.Object{}, &example.Domain{}); err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range []interface{}{
+		&example.User{ID: 1, Email: "user1@example.com", PhoneNumber: "1"},
+		&example.User{ID: 2, Email: "user2@example.com", PhoneNumber: "2"},
+		&example.User{ID: 3, Email: "user3@example.com", PhoneNumber: "3"},
+		&example.Domain{ID: 1, Name: "domain_1"},
+		&example.Role{ID: 1, Name: "admin", Object: "object_1", DomainID: 1},
+		&example.Role{ID: 2, Name: "member", Object: "object_1", DomainID: 1},
+		&example.Object{ID: 1, Name: "root", Type: example.ObjectTypeDefault, DomainID: 1},
+		&example.Object{ID: 2, Name: "data", Type: example.ObjectTypeDefault, DomainID: 1},
+	} {
+		if err := db.Create(v).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := ioutil.WriteFile(s.policy, []byte(stagePolicy), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// run the CLI with the stage's storages and args, such as "-domain", "domain_1", "roles"
+func (s *stage) run(args ...string) (string, error) {
+	w := &bytes.Buffer{}
+	args = append([]string{"-dsn", s.dsn, "-model", "../configs/casbin_model.conf", "-policy", s.policy}, args...)
+	err := caskincli.Run(s.config, args, w)
+	return w.String(), err
+}
+
+func (s *stage) readPolicy(t *testing.T) string {
+	data, err := ioutil.ReadFile(s.policy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestRunUsage(t *testing.T) {
+	s := newStage(t)
+	for _, args := range [][]string{
+		{},
+		{"unknown"},
+		{"-output", "xml", "domains"},
+		{"roles"},
+		{"-domain", "domain_1", "assign", "user_3", "role_2"},
+		{"-domain", "domain_1", "explain", "user_2", "object_2"},
+	} {
+		if _, err := s.run(args...); !errors.Is(err, caskincli.ErrUsage) {
+			t.Fatal(args, err)
+		}
+	}
+}
+
+func TestRunList(t *testing.T) {
+	s := newStage(t)
+	out, err := s.run("-domain", "domain_1", "roles")
+	if err != nil || !strings.Contains(out, "role_1") || !strings.Contains(out, "role_2") {
+		t.Fatal(out, err)
+	}
+	out, err = s.run("-domain", "domain_1", "-output", "json", "policies")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var policies []map[string]string
+	if err := json.Unmarshal([]byte(out), &policies); err != nil || len(policies) != 2 {
+		t.Fatal(out, err)
+	}
+}
+
+func TestRunExplain(t *testing.T) {
+	s := newStage(t)
+	out, err := s.run("-domain", "domain_1", "-output", "json", "explain", "user_2", "object_2", "read")
+	if err != nil {
+		t.Fatal(err)
+	}
+	explanation := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(out), &explanation); err != nil || explanation["allowed"] != false {
+		t.Fatal(out, err)
+	}
+	out, err = s.run("-domain", "domain_1", "-output", "json", "explain", "user_2", "object_2", "write")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal([]byte(out), &explanation); err != nil || explanation["allowed"] != true {
+		t.Fatal(out, err)
+	}
+
+	out, err = s.run("-domain", "domain_1", "-output", "json", "who-can", "object_2", "read")
+	if err != nil {
+		t.Fatal(err)
+	}
+	who := struct{ Roles, Users []string }{}
+	if err := json.Unmarshal([]byte(out), &who); err != nil || fmt.Sprint(who.Roles) != "[role_2]" {
+		t.Fatal(out, err)
+	}
+}
+
+func TestRunAssign(t *testing.T) {
+	s := newStage(t)
+	if _, err := s.run("-domain", "domain_1", "-as", "user_1", "assign", "user_3", "role_2"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(s.readPolicy(t), "g, user_3, role_2, domain_1") {
+		t.Fatal(s.readPolicy(t))
+	}
+
+	// user 3 has no write permission of member
+	if _, err := s.run("-domain", "domain_1", "-as", "user_3", "unassign", "user_3", "role_2"); !errors.Is(err, caskin.ErrNoWritePermission) {
+		t.Fatal(err)
+	}
+	if _, err := s.run("-domain", "domain_1", "-as", "user_2", "unassign", "user_3", "role_2"); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(s.readPolicy(t), "g, user_3, role_2, domain_1") {
+		t.Fatal(s.readPolicy(t))
+	}
+}
+
+func TestRunCheck(t *testing.T) {
+	s := newStage(t)
+	out, err := s.run("-as", "user_1", "check")
+	if err != nil || !strings.Contains(out, "role_9") {
+		t.Fatal(out, err)
+	}
+	// checking without -repair never saves the policies
+	if s.readPolicy(t) != stagePolicy {
+		t.Fatal(s.readPolicy(t))
+	}
+
+	if _, err := s.run("-as", "user_1", "check", "-repair"); err != nil {
+		t.Fatal(err)
+	}
+	if policy := s.readPolicy(t); strings.Contains(policy, "role_9") || !strings.Contains(policy, "g, user_2, role_1, domain_1") {
+		t.Fatal(policy)
+	}
+	if out, err := s.run("-as", "user_1", "-output", "json", "check"); err != nil || strings.Contains(out, "role_9") {
+		t.Fatal(out, err)
+	}
+}
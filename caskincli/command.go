@@ -0,0 +1,544 @@
+package caskincli
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	"github.com/awatercolorpen/caskin"
+)
+
+var (
+	ErrUsage = fmt.Errorf("usage")
+)
+
+// command a subcommand of the CLI
+type command struct {
+	name  string
+	usage string
+	help  string
+	// it needs -domain
+	domain bool
+	// it runs caskin's executor as -as
+	operator bool
+	// it saves the policies after running, check saves by itself only with -repair
+	save bool
+	run  func(c *cli, args []string) error
+}
+
+var commands []*command
+
+func init() {
+	commands = []*command{
+		{name: "domains", usage: "domains", help: "list all domains",
+			run: listDomains},
+		{name: "roles", usage: "roles", help: "list roles of the domain", domain: true,
+			run: listRoles},
+		{name: "objects", usage: "objects [type]", help: "list objects of the domain", domain: true,
+			run: listObjects},
+		{name: "policies", usage: "policies", help: "list policies of the domain", domain: true,
+			run: listPolicies},
+		{name: "assign", usage: "assign <user> <role>", help: "assign the role to the user in the domain", domain: true, operator: true, save: true,
+			run: assign(true)},
+		{name: "unassign", usage: "unassign <user> <role>", help: "unassign the role from the user in the domain", domain: true, operator: true, save: true,
+			run: assign(false)},
+		{name: "superadmins", usage: "superadmins", help: "list superadmin users", operator: true,
+			run: listSuperadmins},
+		{name: "add-superadmin", usage: "add-superadmin <user>", help: "add the user as superadmin", operator: true, save: true,
+			run: writeSuperadmin(true)},
+		{name: "delete-superadmin", usage: "delete-superadmin <user>", help: "delete the user from superadmin", operator: true, save: true,
+			run: writeSuperadmin(false)},
+		{name: "explain", usage: "explain <user> <object> <action>", help: "explain why the user can or can't do the action on the object", domain: true,
+			run: explain},
+		{name: "who-can", usage: "who-can <object> <action>", help: "list roles and users who can do the action on the object", domain: true,
+			run: whoCan},
+		{name: "export", usage: "export [file]", help: "export the domain as JSON archive to the file or stdout", domain: true, operator: true,
+			run: export},
+		{name: "import", usage: "import <file>", help: "import the JSON archive into the domain", domain: true, operator: true, save: true,
+			run: importArchive},
+		{name: "check", usage: "check [-repair [-soft-deleted]]", help: "check casbin rules against metadata database, -repair removes bad rules and saves, -soft-deleted removes rules of soft deleted entries too", operator: true,
+			run: check},
+		{name: "graph", usage: "graph [-format dot|mermaid] [-user u] [-object o] [-label k] [file]", help: "export users, roles, objects and policies of the domain as graph", domain: true, operator: true,
+			run: graph},
+		{name: "matrix", usage: "matrix [-format csv|markdown|html] [file]", help: "report roles' actions on objects of the domain, * marks inherited ones", domain: true, operator: true,
+			run: matrix},
+		{name: "compare", usage: "compare [-role] <a> <b>", help: "compare effective accesses of two users, or policies of two roles with -role", domain: true, operator: true,
+			run: compare},
+		{name: "copy-access", usage: "copy-access <user> <from>", help: "assign the roles of the from user to the user in the domain", domain: true, operator: true, save: true,
+			run: copyAccess},
+		{name: "repl", usage: "repl", help: "explore the permission graph interactively, it never saves",
+			run: runREPL},
+	}
+}
+
+func findCommand(name string) (*command, bool) {
+	for _, v := range commands {
+		if v.name == name {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func checkArgs(args []string, n int) error {
+	if len(args) != n {
+		return fmt.Errorf("%w: expect %v arguments but got %v", ErrUsage, n, len(args))
+	}
+	return nil
+}
+
+func listDomains(c *cli, args []string) error {
+	domains, err := c.mdb.GetAllDomain()
+	if err != nil {
+		return err
+	}
+
+	t := newTable(domains, "CODE", "DATA")
+	for _, v := range domains {
+		t.add(v.Encode(), compactJSON(v))
+	}
+	return c.print(t)
+}
+
+func listRoles(c *cli, args []string) error {
+	roles, err := c.mdb.GetRoleInDomain(c.domain)
+	if err != nil {
+		return err
+	}
+	tree := map[uint64]uint64{}
+	for _, v := range caskin.NewEnforcer(c.casbin, c.factory).GetRolesInDomain(c.domain) {
+		if v.GetParentID() != 0 {
+			tree[v.GetID()] = v.GetParentID()
+		}
+	}
+
+	t := newTable(roles, "CODE", "PARENT", "DATA")
+	for _, v := range roles {
+		parent := ""
+		if id, ok := tree[v.GetID()]; ok {
+			v.SetParentID(id)
+			r := c.factory.NewRole()
+			r.SetID(id)
+			parent = r.Encode()
+		}
+		t.add(v.Encode(), parent, compactJSON(v))
+	}
+	return c.print(t)
+}
+
+func listObjects(c *cli, args []string) error {
+	var ty []caskin.ObjectType
+	for _, v := range args {
+		ty = append(ty, caskin.ObjectType(v))
+	}
+	objects, err := c.mdb.GetObjectInDomain(c.domain, ty...)
+	if err != nil {
+		return err
+	}
+	tree := map[uint64]uint64{}
+	for _, v := range caskin.NewEnforcer(c.casbin, c.factory).GetObjectsInDomain(c.domain) {
+		if v.GetParentID() != 0 {
+			tree[v.GetID()] = v.GetParentID()
+		}
+	}
+
+	t := newTable(objects, "CODE", "PARENT", "DATA")
+	for _, v := range objects {
+		parent := ""
+		if id, ok := tree[v.GetID()]; ok {
+			v.SetParentID(id)
+			o := c.factory.NewObject()
+			o.SetID(id)
+			parent = o.Encode()
+		}
+		t.add(v.Encode(), parent, compactJSON(v))
+	}
+	return c.print(t)
+}
+
+func listPolicies(c *cli, args []string) error {
+	policies := caskin.NewEnforcer(c.casbin, c.factory).GetPoliciesInDomain(c.domain)
+
+	var value []*policyJSON
+	t := newTable(nil, "ROLE", "OBJECT", "ACTION")
+	for _, v := range policies {
+		p := &policyJSON{Role: v.Role.Encode(), Object: v.Object.Encode(), Action: v.Action}
+		value = append(value, p)
+		t.add(p.Role, p.Object, string(p.Action))
+	}
+	t.value = value
+	return c.print(t)
+}
+
+// assign add or remove the user of the role by ModifyUsersForRole as the operator
+func assign(add bool) func(c *cli, args []string) error {
+	return func(c *cli, args []string) error {
+		if err := checkArgs(args, 2); err != nil {
+			return err
+		}
+		user, err := c.decodeUser(args[0], "user")
+		if err != nil {
+			return err
+		}
+		role, err := c.decodeRole(args[1], "role")
+		if err != nil {
+			return err
+		}
+
+		ur := &caskin.UsersForRole{Role: role}
+		for _, v := range caskin.NewEnforcer(c.casbin, c.factory).GetUsersForRoleInDomain(role, c.domain) {
+			if v.GetID() != user.GetID() {
+				ur.Users = append(ur.Users, v)
+			}
+		}
+		if add {
+			ur.Users = append(ur.Users, user)
+		}
+
+		return c.executor(c).ModifyUsersForRole(ur)
+	}
+}
+
+func listSuperadmins(c *cli, args []string) error {
+	users, err := c.executor(c).GetAllSuperadminUser()
+	if err != nil {
+		return err
+	}
+
+	t := newTable(users, "CODE", "DATA")
+	for _, v := range users {
+		t.add(v.Encode(), compactJSON(v))
+	}
+	return c.print(t)
+}
+
+func writeSuperadmin(add bool) func(c *cli, args []string) error {
+	return func(c *cli, args []string) error {
+		if err := checkArgs(args, 1); err != nil {
+			return err
+		}
+		user, err := c.decodeUser(args[0], "user")
+		if err != nil {
+			return err
+		}
+
+		if add {
+			return c.executor(c).AddSuperadminUser(user)
+		}
+		return c.executor(c).DeleteSuperadminUser(user)
+	}
+}
+
+func explain(c *cli, args []string) error {
+	if err := checkArgs(args, 3); err != nil {
+		return err
+	}
+	user, err := c.decodeUser(args[0], "user")
+	if err != nil {
+		return err
+	}
+	object, err := c.decodeObject(args[1], "object")
+	if err != nil {
+		return err
+	}
+
+	explanation, err := caskin.Explain(caskin.NewEnforcer(c.casbin, c.factory), user, object, c.domain, caskin.Action(args[2]))
+	if err != nil {
+		return err
+	}
+
+	return c.print(explanationTable(explanation))
+}
+
+func explanationTable(explanation *caskin.Explanation) *table {
+	value := newExplanationJSON(explanation)
+	t := newTable(value, "FIELD", "VALUE")
+	t.add("allowed", strconv.FormatBool(value.Allowed))
+	t.add("superadmin", strconv.FormatBool(value.Superadmin))
+	t.add("domain_admin", strconv.FormatBool(value.DomainAdmin))
+	for _, v := range value.Grants {
+		t.add("grant", v.String())
+	}
+	return t
+}
+
+func whoCan(c *cli, args []string) error {
+	if err := checkArgs(args, 2); err != nil {
+		return err
+	}
+	object, err := c.decodeObject(args[0], "object")
+	if err != nil {
+		return err
+	}
+
+	roles, users := caskin.WhoCan(caskin.NewEnforcer(c.casbin, c.factory), object, c.domain, caskin.Action(args[1]))
+	return c.print(whoCanTable(roles, users))
+}
+
+func whoCanTable(roles []caskin.Role, users []caskin.User) *table {
+	value := &whoCanJSON{}
+	t := newTable(value, "KIND", "CODE")
+	for _, v := range roles {
+		value.Roles = append(value.Roles, v.Encode())
+		t.add("role", v.Encode())
+	}
+	for _, v := range users {
+		value.Users = append(value.Users, v.Encode())
+		t.add("user", v.Encode())
+	}
+	return t
+}
+
+// export export the domain as the operator
+func export(c *cli, args []string) error {
+	if len(args) > 1 {
+		return checkArgs(args, 1)
+	}
+
+	archive, err := c.executor(c).ExportDomain(c.domain)
+	if err != nil {
+		return err
+	}
+	data, err := caskin.EncodeDomainArchive(archive)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		_, err = fmt.Fprintln(c.w, string(data))
+		return err
+	}
+	return ioutil.WriteFile(args[0], data, 0644)
+}
+
+func importArchive(c *cli, args []string) error {
+	if err := checkArgs(args, 1); err != nil {
+		return err
+	}
+	data, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	archive, err := caskin.DecodeDomainArchive(data, c.factory)
+	if err != nil {
+		return err
+	}
+
+	result, err := c.executor(c).ImportDomain(archive, c.domain)
+	if err != nil {
+		return err
+	}
+
+	t := newTable(result, "KIND", "SOURCE", "TARGET")
+	for k, v := range result.RoleID {
+		t.add("role", strconv.FormatUint(k, 10), strconv.FormatUint(v, 10))
+	}
+	for k, v := range result.ObjectID {
+		t.add("object", strconv.FormatUint(k, 10), strconv.FormatUint(v, 10))
+	}
+	for _, v := range result.MissingUsers {
+		t.add("missing_user", strconv.FormatUint(v, 10), "")
+	}
+	t.sort()
+	return c.print(t)
+}
+
+func check(c *cli, args []string) error {
+	fs := flag.NewFlagSet("check", flag.ContinueOnError)
+	fs.SetOutput(c.w)
+	repair := fs.Bool("repair", false, "remove the inconsistent rules")
+	softDeleted := fs.Bool("soft-deleted", false, "remove the rules of soft deleted entries too when repairing")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	fn := c.executor(c).VerifyConsistency
+	if *repair {
+		fn = func() (*caskin.ConsistencyReport, error) {
+			return c.executor(c).RepairConsistency(*softDeleted)
+		}
+	}
+	report, err := fn()
+	if err != nil {
+		return err
+	}
+
+	if *repair {
+		if err := c.casbin.SavePolicy(); err != nil {
+			return err
+		}
+	}
+
+	t := newTable(report, "TYPE", "PTYPE", "RULE", "REASON")
+	for _, v := range report.Issues {
+		t.add(string(v.Type), v.Rule.PType, fmt.Sprint(v.Rule.Values), v.Reason)
+	}
+	return c.print(t)
+}
+
+func graph(c *cli, args []string) error {
+	fs := flag.NewFlagSet("graph", flag.ContinueOnError)
+	fs.SetOutput(c.w)
+	format := fs.String("format", "dot", "graph format, dot or mermaid")
+	user := fs.String("user", "", "only the user's roles and their policies")
+	object := fs.String("object", "", "only the object's subtree and its policies")
+	label := fs.String("label", "", "comma separated keys of entry's metadata in the labels, such as name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return checkArgs(fs.Args(), 1)
+	}
+	if *format != "dot" && *format != "mermaid" {
+		return fmt.Errorf("%w: unknown format %v", ErrUsage, *format)
+	}
+
+	option := &caskin.GraphOption{}
+	if *user != "" {
+		u, err := c.decodeUser(*user, "-user")
+		if err != nil {
+			return err
+		}
+		option.User = u
+	}
+	if *object != "" {
+		o, err := c.decodeObject(*object, "-object")
+		if err != nil {
+			return err
+		}
+		option.Object = o
+	}
+	if *label != "" {
+		option.LabelKeys = strings.Split(*label, ",")
+	}
+
+	g, err := c.executor(c).ExportGraph(c.domain, option)
+	if err != nil {
+		return err
+	}
+
+	data := g.DOT()
+	if *format == "mermaid" {
+		data = g.Mermaid()
+	}
+
+	if fs.NArg() == 0 {
+		_, err = fmt.Fprint(c.w, data)
+		return err
+	}
+	return ioutil.WriteFile(fs.Arg(0), []byte(data), 0644)
+}
+
+func matrix(c *cli, args []string) error {
+	fs := flag.NewFlagSet("matrix", flag.ContinueOnError)
+	fs.SetOutput(c.w)
+	format := fs.String("format", "csv", "report format, csv, markdown or html")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 1 {
+		return checkArgs(fs.Args(), 1)
+	}
+	if *format != "csv" && *format != "markdown" && *format != "html" {
+		return fmt.Errorf("%w: unknown format %v", ErrUsage, *format)
+	}
+
+	m, err := c.executor(c).GetPermissionMatrix(c.domain)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	switch *format {
+	case "csv":
+		if data, err = m.CSV(); err != nil {
+			return err
+		}
+	case "markdown":
+		data = []byte(m.Markdown())
+	case "html":
+		data = []byte(m.HTML())
+	}
+
+	if fs.NArg() == 0 {
+		_, err = c.w.Write(data)
+		return err
+	}
+	return ioutil.WriteFile(fs.Arg(0), data, 0644)
+}
+
+func compare(c *cli, args []string) error {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	fs.SetOutput(c.w)
+	role := fs.Bool("role", false, "compare the policies of two roles")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkArgs(fs.Args(), 2); err != nil {
+		return err
+	}
+
+	var result *caskin.AccessComparison
+	if *role {
+		a, err := c.decodeRole(fs.Arg(0), "a")
+		if err != nil {
+			return err
+		}
+		b, err := c.decodeRole(fs.Arg(1), "b")
+		if err != nil {
+			return err
+		}
+		if result, err = c.executor(c).CompareRoleAccess(a, b); err != nil {
+			return err
+		}
+	} else {
+		a, err := c.decodeUser(fs.Arg(0), "a")
+		if err != nil {
+			return err
+		}
+		b, err := c.decodeUser(fs.Arg(1), "b")
+		if err != nil {
+			return err
+		}
+		if result, err = c.executor(c).CompareUserAccess(a, b); err != nil {
+			return err
+		}
+	}
+
+	value := &comparisonJSON{}
+	t := newTable(value, "HAS", "OBJECT", "ACTION")
+	for _, v := range []struct {
+		has      string
+		accesses []*caskin.Access
+		out      *[]*accessJSON
+	}{
+		{fs.Arg(0), result.OnlyA, &value.OnlyA},
+		{fs.Arg(1), result.OnlyB, &value.OnlyB},
+		{"both", result.Shared, &value.Shared},
+	} {
+		*v.out = []*accessJSON{}
+		for _, a := range v.accesses {
+			*v.out = append(*v.out, &accessJSON{Object: a.Object.Encode(), Action: a.Action})
+			t.add(v.has, a.Object.Encode(), string(a.Action))
+		}
+	}
+	return c.print(t)
+}
+
+func copyAccess(c *cli, args []string) error {
+	if err := checkArgs(args, 2); err != nil {
+		return err
+	}
+	user, err := c.decodeUser(args[0], "user")
+	if err != nil {
+		return err
+	}
+	from, err := c.decodeUser(args[1], "from")
+	if err != nil {
+		return err
+	}
+
+	return c.executor(c).CopyAccess(user, from)
+}
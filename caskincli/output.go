@@ -0,0 +1,127 @@
+package caskincli
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/awatercolorpen/caskin"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// table the result of a command, printed as the rows for table output or as the value for JSON output
+type table struct {
+	value  interface{}
+	header []string
+	rows   [][]string
+}
+
+func newTable(value interface{}, header ...string) *table {
+	return &table{value: value, header: header}
+}
+
+func (t *table) add(row ...string) {
+	t.rows = append(t.rows, row)
+}
+
+func (t *table) sort() {
+	sort.SliceStable(t.rows, func(i, j int) bool {
+		return strings.Join(t.rows[i], "\t") < strings.Join(t.rows[j], "\t")
+	})
+}
+
+func (c *cli) print(t *table) error {
+	if c.output == outputJSON {
+		data, err := json.MarshalIndent(t.value, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(c.w, string(data))
+		return err
+	}
+
+	w := tabwriter.NewWriter(c.w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(t.header, "\t"))
+	for _, v := range t.rows {
+		fmt.Fprintln(w, strings.Join(v, "\t"))
+	}
+	return w.Flush()
+}
+
+func compactJSON(v interface{}) string {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err.Error()
+	}
+	return string(data)
+}
+
+type policyJSON struct {
+	Role   string        `json:"role"`
+	Object string        `json:"object"`
+	Action caskin.Action `json:"action"`
+}
+
+type whoCanJSON struct {
+	Roles []string `json:"roles"`
+	Users []string `json:"users"`
+}
+
+type accessJSON struct {
+	Object string        `json:"object"`
+	Action caskin.Action `json:"action"`
+}
+
+type comparisonJSON struct {
+	OnlyA  []*accessJSON `json:"only_a"`
+	OnlyB  []*accessJSON `json:"only_b"`
+	Shared []*accessJSON `json:"shared"`
+}
+
+type explanationJSON struct {
+	Allowed     bool         `json:"allowed"`
+	Superadmin  bool         `json:"superadmin"`
+	DomainAdmin bool         `json:"domain_admin"`
+	Grants      []*grantJSON `json:"grants"`
+}
+
+type grantJSON struct {
+	Roles   []string   `json:"roles"`
+	Objects []string   `json:"objects"`
+	Policy  policyJSON `json:"policy"`
+}
+
+func newExplanationJSON(explanation *caskin.Explanation) *explanationJSON {
+	out := &explanationJSON{
+		Allowed:     explanation.Allowed,
+		Superadmin:  explanation.Superadmin,
+		DomainAdmin: explanation.DomainAdmin,
+	}
+	for _, v := range explanation.Grants {
+		g := &grantJSON{Policy: policyJSON{
+			Role:   v.Policy.Role.Encode(),
+			Object: v.Policy.Object.Encode(),
+			Action: v.Policy.Action,
+		}}
+		for _, r := range v.Roles {
+			g.Roles = append(g.Roles, r.Encode())
+		}
+		for _, o := range v.Objects {
+			g.Objects = append(g.Objects, o.Encode())
+		}
+		out.Grants = append(out.Grants, g)
+	}
+	return out
+}
+
+// String such as "r:1 > r:2 can read object_3 < object_1"
+func (g *grantJSON) String() string {
+	return fmt.Sprintf("%v can %v %v",
+		strings.Join(g.Roles, " > "), g.Policy.Action, strings.Join(g.Objects, " < "))
+}
@@ -0,0 +1,453 @@
+package caskincli
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/peterh/liner"
+)
+
+// replCommand a command of the REPL
+type replCommand struct {
+	usage string
+	help  string
+	run   func(r *repl, args []string) error
+	// complete the n-th argument
+	complete func(r *repl, n int, args []string) []string
+}
+
+var replCommands map[string]*replCommand
+
+func init() {
+	replCommands = map[string]*replCommand{
+		"use": {usage: "use domain <code|id>", help: "use the domain as current domain",
+			run: replUse, complete: replCompleteUse},
+		"as": {usage: "as user <code|id>", help: "act as the user in current domain",
+			run: replAs, complete: replCompleteAs},
+		"can": {usage: "can <object> <action>", help: "check current user's action on the object",
+			run: replCan, complete: replCompleteObjectAction},
+		"explain": {usage: "explain <object> <action>", help: "explain current user's action on the object",
+			run: replExplain, complete: replCompleteObjectAction},
+		"who-can": {usage: "who-can <object> <action>", help: "list roles and users who can do the action on the object",
+			run: replWhoCan, complete: replCompleteObjectAction},
+		"roles": {usage: "roles", help: "list current user's roles and the roles they inherit",
+			run: replRoles},
+		"tree": {usage: "tree roles|objects", help: "print the role or object hierarchy of current domain",
+			run: replTree, complete: replCompleteTree},
+		"help": {usage: "help", help: "print the commands",
+			run: replHelp},
+	}
+}
+
+// repl the state of the REPL, it reads casbin rules and metadata database but never saves
+type repl struct {
+	*cli
+	user   caskin.User
+	domain caskin.Domain
+}
+
+// runREPL read lines from stdin with tab completion until exit or EOF
+func runREPL(c *cli, args []string) error {
+	r := &repl{cli: c}
+	if c.operator.GetID() != 0 {
+		r.user = c.operator
+	}
+	if c.domain.GetID() != 0 {
+		r.domain = c.domain
+	}
+
+	line := liner.NewLiner()
+	defer line.Close()
+	line.SetCtrlCAborts(true)
+	line.SetCompleter(r.complete)
+
+	fmt.Fprintln(c.w, "caskin REPL, type help for commands, exit to quit")
+	for {
+		input, err := line.Prompt(r.prompt())
+		if err == io.EOF || err == liner.ErrPromptAborted {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		input = strings.TrimSpace(input)
+		if input == "" {
+			continue
+		}
+		line.AppendHistory(input)
+		if input == "exit" || input == "quit" {
+			return nil
+		}
+		if err := r.exec(input); err != nil {
+			fmt.Fprintln(c.w, "error:", err)
+		}
+	}
+}
+
+func (r *repl) prompt() string {
+	user, domain := "-", "-"
+	if r.user != nil {
+		user = r.user.Encode()
+	}
+	if r.domain != nil {
+		domain = r.domain.Encode()
+	}
+	return fmt.Sprintf("%v@%v> ", user, domain)
+}
+
+func (r *repl) exec(input string) error {
+	fields := strings.Fields(input)
+	cmd, ok := replCommands[fields[0]]
+	if !ok {
+		return fmt.Errorf("%w: unknown command %v", ErrUsage, fields[0])
+	}
+	return cmd.run(r, fields[1:])
+}
+
+// complete the last word of the line by the command and the decoded entries
+func (r *repl) complete(line string) []string {
+	fields := strings.Fields(line)
+	if len(fields) == 0 || !strings.HasSuffix(line, " ") {
+		// the last field is being typed
+		if len(fields) > 0 {
+			fields = fields[:len(fields)-1]
+		}
+	}
+	prefix := strings.Join(fields, " ")
+	if prefix != "" {
+		prefix += " "
+	}
+	word := strings.TrimPrefix(line, prefix)
+
+	var candidates []string
+	if len(fields) == 0 {
+		for k := range replCommands {
+			candidates = append(candidates, k)
+		}
+		candidates = append(candidates, "exit")
+	} else if cmd, ok := replCommands[fields[0]]; ok && cmd.complete != nil {
+		candidates = cmd.complete(r, len(fields)-1, fields[1:])
+	}
+
+	var out []string
+	for _, v := range candidates {
+		if strings.HasPrefix(v, word) {
+			out = append(out, prefix+v)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (r *repl) current() (caskin.User, caskin.Domain, error) {
+	if r.domain == nil {
+		return nil, nil, fmt.Errorf("%w: use domain first", ErrUsage)
+	}
+	if r.user == nil {
+		return nil, nil, fmt.Errorf("%w: act as user first", ErrUsage)
+	}
+	return r.user, r.domain, nil
+}
+
+// decodeCodeOrID decode the code, or set the id if it is a number
+func decodeCodeOrID(one interface {
+	Decode(string) error
+	SetID(uint64)
+}, s string) error {
+	if id, err := strconv.ParseUint(s, 10, 64); err == nil {
+		one.SetID(id)
+		return nil
+	}
+	return one.Decode(s)
+}
+
+func replUse(r *repl, args []string) error {
+	if len(args) != 2 || args[0] != "domain" {
+		return fmt.Errorf("%w: use domain <code|id>", ErrUsage)
+	}
+	domain := r.factory.NewDomain()
+	if err := decodeCodeOrID(domain, args[1]); err != nil {
+		return err
+	}
+	if err := r.mdb.TakeDomain(domain); err != nil {
+		return &caskin.NotFoundError{Kind: caskin.DomainEntry, ID: domain.GetID()}
+	}
+	r.domain = domain
+	return nil
+}
+
+func replAs(r *repl, args []string) error {
+	if len(args) != 2 || args[0] != "user" {
+		return fmt.Errorf("%w: as user <code|id>", ErrUsage)
+	}
+	user := r.factory.NewUser()
+	if err := decodeCodeOrID(user, args[1]); err != nil {
+		return err
+	}
+	if err := r.mdb.TakeUser(user); err != nil {
+		return &caskin.NotFoundError{Kind: caskin.UserEntry, ID: user.GetID()}
+	}
+	r.user = user
+	return nil
+}
+
+// objectAction decode the arguments in the same order as the CLI's explain and who-can
+func (r *repl) objectAction(args []string) (caskin.Object, caskin.Action, error) {
+	if len(args) != 2 {
+		return nil, "", fmt.Errorf("%w: expect <object> <action>", ErrUsage)
+	}
+	object := r.factory.NewObject()
+	if err := decodeCodeOrID(object, args[0]); err != nil {
+		return nil, "", err
+	}
+	return object, caskin.Action(args[1]), nil
+}
+
+func replCan(r *repl, args []string) error {
+	user, domain, err := r.current()
+	if err != nil {
+		return err
+	}
+	object, action, err := r.objectAction(args)
+	if err != nil {
+		return err
+	}
+
+	ok, err := caskin.NewEnforcer(r.casbin, r.factory).Enforce(user, object, domain, action)
+	if err != nil {
+		return err
+	}
+	answer := "no"
+	if ok {
+		answer = "yes"
+	}
+	_, err = fmt.Fprintln(r.w, answer)
+	return err
+}
+
+func replExplain(r *repl, args []string) error {
+	user, domain, err := r.current()
+	if err != nil {
+		return err
+	}
+	object, action, err := r.objectAction(args)
+	if err != nil {
+		return err
+	}
+
+	explanation, err := caskin.Explain(caskin.NewEnforcer(r.casbin, r.factory), user, object, domain, action)
+	if err != nil {
+		return err
+	}
+
+	return r.print(explanationTable(explanation))
+}
+
+func replWhoCan(r *repl, args []string) error {
+	if r.domain == nil {
+		return fmt.Errorf("%w: use domain first", ErrUsage)
+	}
+	object, action, err := r.objectAction(args)
+	if err != nil {
+		return err
+	}
+
+	roles, users := caskin.WhoCan(caskin.NewEnforcer(r.casbin, r.factory), object, r.domain, action)
+	return r.print(whoCanTable(roles, users))
+}
+
+func replRoles(r *repl, args []string) error {
+	user, domain, err := r.current()
+	if err != nil {
+		return err
+	}
+
+	e := caskin.NewEnforcer(r.casbin, r.factory)
+	children := childrenOf(e.GetRolesInDomain(domain))
+	for _, v := range e.GetRolesForUserInDomain(user, domain) {
+		printTree(r.w, v.Encode(), children, "", map[string]bool{})
+	}
+	return nil
+}
+
+func replTree(r *repl, args []string) error {
+	if r.domain == nil {
+		return fmt.Errorf("%w: use domain first", ErrUsage)
+	}
+	if len(args) != 1 {
+		return fmt.Errorf("%w: tree roles|objects", ErrUsage)
+	}
+
+	e := caskin.NewEnforcer(r.casbin, r.factory)
+	var codes []string
+	var children map[string][]string
+	switch args[0] {
+	case "roles":
+		roles, err := r.mdb.GetRoleInDomain(r.domain)
+		if err != nil {
+			return err
+		}
+		for _, v := range roles {
+			codes = append(codes, v.Encode())
+		}
+		children = childrenOf(e.GetRolesInDomain(r.domain))
+	case "objects":
+		objects, err := r.mdb.GetObjectInDomain(r.domain)
+		if err != nil {
+			return err
+		}
+		for _, v := range objects {
+			codes = append(codes, v.Encode())
+		}
+		children = childrenOf(e.GetObjectsInDomain(r.domain))
+	default:
+		return fmt.Errorf("%w: tree roles|objects", ErrUsage)
+	}
+
+	hasParent := map[string]bool{}
+	for _, v := range children {
+		for _, c := range v {
+			hasParent[c] = true
+		}
+	}
+	seen := map[string]bool{}
+	for _, v := range codes {
+		if !hasParent[v] {
+			printTree(r.w, v, children, "", seen)
+		}
+	}
+	return nil
+}
+
+func replHelp(r *repl, args []string) error {
+	var names []string
+	for k := range replCommands {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+	for _, v := range names {
+		fmt.Fprintf(r.w, "  %-28v %v\n", replCommands[v].usage, replCommands[v].help)
+	}
+	fmt.Fprintf(r.w, "  %-28v %v\n", "exit", "quit the REPL")
+	return nil
+}
+
+// childrenOf get parent's code to children's codes of the entries with parent id
+func childrenOf(source interface{}) map[string][]string {
+	children := map[string][]string{}
+	add := func(code string, parent string) {
+		for _, v := range children[parent] {
+			if v == code {
+				return
+			}
+		}
+		children[parent] = append(children[parent], code)
+	}
+	switch entries := source.(type) {
+	case []caskin.Role:
+		for _, v := range entries {
+			if v.GetParentID() != 0 {
+				p := cloneWithID(v, v.GetParentID())
+				add(v.Encode(), p)
+			}
+		}
+	case []caskin.Object:
+		for _, v := range entries {
+			if v.GetParentID() != 0 {
+				p := cloneWithID(v, v.GetParentID())
+				add(v.Encode(), p)
+			}
+		}
+	}
+	for _, v := range children {
+		sort.Strings(v)
+	}
+	return children
+}
+
+// cloneWithID encode the id with the entry's codec, the entry is restored after encoding
+func cloneWithID(one interface {
+	GetID() uint64
+	SetID(uint64)
+	Encode() string
+}, id uint64) string {
+	origin := one.GetID()
+	one.SetID(id)
+	code := one.Encode()
+	one.SetID(origin)
+	return code
+}
+
+func printTree(w io.Writer, code string, children map[string][]string, indent string, seen map[string]bool) {
+	if seen[code] {
+		fmt.Fprintf(w, "%v%v (cycle)\n", indent, code)
+		return
+	}
+	seen[code] = true
+	fmt.Fprintf(w, "%v%v\n", indent, code)
+	for _, v := range children[code] {
+		printTree(w, v, children, indent+"  ", seen)
+	}
+}
+
+func replCompleteUse(r *repl, n int, args []string) []string {
+	switch n {
+	case 0:
+		return []string{"domain"}
+	case 1:
+		domains, _ := r.mdb.GetAllDomain()
+		var codes []string
+		for _, v := range domains {
+			codes = append(codes, v.Encode())
+		}
+		return codes
+	}
+	return nil
+}
+
+func replCompleteAs(r *repl, n int, args []string) []string {
+	switch n {
+	case 0:
+		return []string{"user"}
+	case 1:
+		if r.domain == nil {
+			return nil
+		}
+		var codes []string
+		for _, v := range caskin.NewEnforcer(r.casbin, r.factory).GetUsersInDomain(r.domain) {
+			codes = append(codes, v.Encode())
+		}
+		return codes
+	}
+	return nil
+}
+
+func replCompleteObjectAction(r *repl, n int, args []string) []string {
+	switch n {
+	case 0:
+		if r.domain == nil {
+			return nil
+		}
+		objects, _ := r.mdb.GetObjectInDomain(r.domain)
+		var codes []string
+		for _, v := range objects {
+			codes = append(codes, v.Encode())
+		}
+		return codes
+	case 1:
+		return []string{string(caskin.Read), string(caskin.Write)}
+	}
+	return nil
+}
+
+func replCompleteTree(r *repl, n int, args []string) []string {
+	if n == 0 {
+		return []string{"roles", "objects"}
+	}
+	return nil
+}
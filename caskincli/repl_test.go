@@ -0,0 +1,74 @@
+package caskincli_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+// runREPL run the REPL with the input lines as stdin, which is not a terminal then
+func (s *stage) runREPL(t *testing.T, input ...string) string {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.WriteString(strings.Join(input, "\n") + "\n"); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = stdin }()
+
+	out, err := s.run("repl")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestREPL(t *testing.T) {
+	s := newStage(t)
+	out := s.runREPL(t,
+		"can object_2 write",
+		"use domain domain_1",
+		"as user user_2",
+		"can object_2 write",
+		"can object_2 read",
+		"explain object_2 write",
+		"who-can object_2 read",
+		"tree objects",
+		"roles",
+		"can write object_2",
+		"exit",
+		"as user user_3",
+	)
+
+	for _, v := range []string{
+		"error: usage: use domain first",
+		"yes\nno\n",
+		"grant         role_1 can write object_2 < object_1",
+		"role  role_2",
+		"object_1\n  object_2\n",
+		"\nrole_1\n",
+		"error: input does not match format",
+	} {
+		if !strings.Contains(out, v) {
+			t.Fatalf("%q is not in output:\n%v", v, out)
+		}
+	}
+	// the REPL never saves
+	if s.readPolicy(t) != stagePolicy {
+		t.Fatal(s.readPolicy(t))
+	}
+}
+
+func TestREPLNotFound(t *testing.T) {
+	s := newStage(t)
+	out := s.runREPL(t, "use domain domain_9", "as user 9", "tree")
+	for _, v := range []string{"error: not exists: domain 9", "error: not exists: user 9", "error: usage: use domain first"} {
+		if !strings.Contains(out, v) {
+			t.Fatalf("%q is not in output:\n%v", v, out)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package caskingrpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/awatercolorpen/caskin"
+	"google.golang.org/grpc"
+)
+
+// Authorizer the interceptors protecting methods by caskin's objects
+type Authorizer struct {
+	enforcer caskin.Enforcer
+	auth     Authenticator
+	unmapped caskin.Unmapped
+	// full method to permission
+	methods map[string]*caskin.Permission
+}
+
+// NewAuthorizer build the Authorizer, MetadataAuthenticator provides current user and domain from metadata
+func NewAuthorizer(enforcer caskin.Enforcer, auth Authenticator, unmapped caskin.Unmapped) *Authorizer {
+	return &Authorizer{
+		enforcer: enforcer,
+		auth:     auth,
+		unmapped: unmapped,
+		methods:  map[string]*caskin.Permission{},
+	}
+}
+
+// Handle register the fully-qualified method such as "/caskin.Caskin/CreateRole" requiring the action on the object,
+// "/caskin.Caskin/*" registers all methods of the service without their own registration
+func (a *Authorizer) Handle(fullMethod string, object caskin.Object, action caskin.Action) {
+	a.methods[fullMethod] = &caskin.Permission{Object: object, Action: action}
+}
+
+// UnaryInterceptor the grpc.UnaryServerInterceptor authorizing the call before the handler
+func (a *Authorizer) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if err := a.authorize(ctx, info.FullMethod); err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamInterceptor the grpc.StreamServerInterceptor authorizing the stream before the handler
+func (a *Authorizer) StreamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := a.authorize(ss.Context(), info.FullMethod); err != nil {
+			return err
+		}
+		return handler(srv, ss)
+	}
+}
+
+// authorize the call of the method
+// 1. find the permission of the method, deal with unmapped method by caskin.Unmapped
+// 2. authenticate the call by the Authenticator to get current user and domain
+// 3. enforce current user's action on the object in current domain, reject with PermissionDenied if it is not allowed
+func (a *Authorizer) authorize(ctx context.Context, fullMethod string) error {
+	permission := a.permission(fullMethod)
+	if permission == nil {
+		if a.unmapped == caskin.UnmappedAllow {
+			return nil
+		}
+		return toStatus(fmt.Errorf("%w: %v is not mapped", caskin.ErrForbidden, fullMethod))
+	}
+
+	provider, err := a.auth(ctx)
+	if err != nil {
+		return toStatus(fmt.Errorf("%w: %v", caskin.ErrUnauthorized, err))
+	}
+	if err := permission.Authorize(a.enforcer, provider); err != nil {
+		return toStatus(err)
+	}
+
+	return nil
+}
+
+// permission get the permission of the method, or of its service, it is nil if the method is not mapped
+func (a *Authorizer) permission(fullMethod string) *caskin.Permission {
+	if p, ok := a.methods[fullMethod]; ok {
+		return p
+	}
+	if i := strings.LastIndex(fullMethod, "/"); i >= 0 {
+		return a.methods[fullMethod[:i+1]+"*"]
+	}
+	return nil
+}
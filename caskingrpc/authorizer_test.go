@@ -0,0 +1,77 @@
+package caskingrpc_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/caskingrpc"
+	"github.com/awatercolorpen/caskin/example"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// readEnforcer allow user 1 to read every object only
+type readEnforcer struct{}
+
+func (readEnforcer) Enforce(user caskin.User, _ caskin.Object, _ caskin.Domain, action caskin.Action) (bool, error) {
+	return user.GetID() == 1 && action == caskin.Read, nil
+}
+
+func TestAuthorizer(t *testing.T) {
+	factory := example.NewEntryFactory()
+	for _, unmapped := range []caskin.Unmapped{caskin.UnmappedAllow, caskin.UnmappedDeny} {
+		a := caskingrpc.NewAuthorizer(readEnforcer{}, caskingrpc.MetadataAuthenticator(factory), unmapped)
+		a.Handle("/caskin.Caskin/CreateRole", &example.Object{ID: 1}, caskin.Read)
+		a.Handle("/caskin.Caskin/RecoverRole", &example.Object{ID: 1}, caskin.Write)
+		conn, e := dial(t, grpc.UnaryInterceptor(a.UnaryInterceptor()))
+		client := caskingrpc.NewClient(conn, factory)
+		ctx := caskingrpc.WithCurrentUser(context.Background(), &example.User{ID: 1}, &example.Domain{ID: 1})
+
+		if err := client.CreateRole(ctx, &example.Role{Name: "admin"}); err != nil || len(e.created) != 1 {
+			t.Fatal(unmapped, err)
+		}
+		other := caskingrpc.WithCurrentUser(context.Background(), &example.User{ID: 2}, &example.Domain{ID: 1})
+		if err := client.CreateRole(other, &example.Role{Name: "admin"}); !errors.Is(err, caskin.ErrNoReadPermission) || status.Code(err) != codes.PermissionDenied {
+			t.Fatal(unmapped, err)
+		}
+		if err := client.RecoverRole(ctx, &example.Role{ID: 1}); !errors.Is(err, caskin.ErrNoWritePermission) {
+			t.Fatal(unmapped, err)
+		}
+		if err := client.CreateRole(context.Background(), &example.Role{Name: "admin"}); !errors.Is(err, caskin.ErrUnauthorized) || status.Code(err) != codes.Unauthenticated {
+			t.Fatal(unmapped, err)
+		}
+		if len(e.created) != 1 {
+			t.Fatal(unmapped, e.created)
+		}
+
+		// the unmapped call reaches the executor only if it is allowed
+		err := client.DeleteRole(ctx, &example.Role{ID: 1})
+		if unmapped == caskin.UnmappedAllow && !errors.Is(err, caskin.ErrNoWritePermission) {
+			t.Fatal(unmapped, err)
+		}
+		if unmapped == caskin.UnmappedDeny && (!errors.Is(err, caskin.ErrForbidden) || status.Code(err) != codes.PermissionDenied) {
+			t.Fatal(unmapped, err)
+		}
+	}
+}
+
+func TestAuthorizerService(t *testing.T) {
+	factory := example.NewEntryFactory()
+	a := caskingrpc.NewAuthorizer(readEnforcer{}, caskingrpc.MetadataAuthenticator(factory), caskin.UnmappedAllow)
+	a.Handle("/caskin.Caskin/*", &example.Object{ID: 1}, caskin.Write)
+	a.Handle("/caskin.Caskin/CreateRole", &example.Object{ID: 1}, caskin.Read)
+	conn, _ := dial(t, grpc.UnaryInterceptor(a.UnaryInterceptor()))
+	client := caskingrpc.NewClient(conn, factory)
+	ctx := caskingrpc.WithCurrentUser(context.Background(), &example.User{ID: 1}, &example.Domain{ID: 1})
+
+	if err := client.CreateRole(ctx, &example.Role{Name: "admin"}); err != nil {
+		t.Fatal(err)
+	}
+	// the method without its own registration requires the service's permission
+	if err := client.RecoverRole(ctx, &example.Role{ID: 1}); !errors.Is(err, caskin.ErrNoWritePermission) {
+		t.Fatal(err)
+	}
+}
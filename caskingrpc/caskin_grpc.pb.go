@@ -0,0 +1,641 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package caskingrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// CaskinClient is the client API for Caskin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type CaskinClient interface {
+	GetAllUsersForRole(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*UsersForRoleList, error)
+	ModifyUsersForRole(ctx context.Context, in *UsersForRole, opts ...grpc.CallOption) (*Empty, error)
+	GetAllRolesForUser(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RolesForUserList, error)
+	ModifyRolesForUser(ctx context.Context, in *RolesForUser, opts ...grpc.CallOption) (*Empty, error)
+	CreateRole(ctx context.Context, in *Role, opts ...grpc.CallOption) (*Role, error)
+	RecoverRole(ctx context.Context, in *Role, opts ...grpc.CallOption) (*Role, error)
+	DeleteRole(ctx context.Context, in *DeleteRoleRequest, opts ...grpc.CallOption) (*Empty, error)
+	DeleteObject(ctx context.Context, in *DeleteObjectRequest, opts ...grpc.CallOption) (*Empty, error)
+	CreateDomain(ctx context.Context, in *Domain, opts ...grpc.CallOption) (*Domain, error)
+	RecoverDomain(ctx context.Context, in *Domain, opts ...grpc.CallOption) (*Domain, error)
+	DeleteDomain(ctx context.Context, in *DeleteDomainRequest, opts ...grpc.CallOption) (*Empty, error)
+	UpdateDomain(ctx context.Context, in *Domain, opts ...grpc.CallOption) (*Empty, error)
+	GetAllDomain(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*DomainList, error)
+	AddSuperadminUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*Empty, error)
+	DeleteSuperadminUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*Empty, error)
+	GetAllSuperadminUser(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*UserList, error)
+}
+
+type caskinClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewCaskinClient(cc grpc.ClientConnInterface) CaskinClient {
+	return &caskinClient{cc}
+}
+
+func (c *caskinClient) GetAllUsersForRole(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*UsersForRoleList, error) {
+	out := new(UsersForRoleList)
+	err := c.cc.Invoke(ctx, "/caskin.Caskin/GetAllUsersForRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *caskinClient) ModifyUsersForRole(ctx context.Context, in *UsersForRole, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/caskin.Caskin/ModifyUsersForRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *caskinClient) GetAllRolesForUser(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*RolesForUserList, error) {
+	out := new(RolesForUserList)
+	err := c.cc.Invoke(ctx, "/caskin.Caskin/GetAllRolesForUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *caskinClient) ModifyRolesForUser(ctx context.Context, in *RolesForUser, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/caskin.Caskin/ModifyRolesForUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *caskinClient) CreateRole(ctx context.Context, in *Role, opts ...grpc.CallOption) (*Role, error) {
+	out := new(Role)
+	err := c.cc.Invoke(ctx, "/caskin.Caskin/CreateRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *caskinClient) RecoverRole(ctx context.Context, in *Role, opts ...grpc.CallOption) (*Role, error) {
+	out := new(Role)
+	err := c.cc.Invoke(ctx, "/caskin.Caskin/RecoverRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *caskinClient) DeleteRole(ctx context.Context, in *DeleteRoleRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/caskin.Caskin/DeleteRole", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *caskinClient) DeleteObject(ctx context.Context, in *DeleteObjectRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/caskin.Caskin/DeleteObject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *caskinClient) CreateDomain(ctx context.Context, in *Domain, opts ...grpc.CallOption) (*Domain, error) {
+	out := new(Domain)
+	err := c.cc.Invoke(ctx, "/caskin.Caskin/CreateDomain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *caskinClient) RecoverDomain(ctx context.Context, in *Domain, opts ...grpc.CallOption) (*Domain, error) {
+	out := new(Domain)
+	err := c.cc.Invoke(ctx, "/caskin.Caskin/RecoverDomain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *caskinClient) DeleteDomain(ctx context.Context, in *DeleteDomainRequest, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/caskin.Caskin/DeleteDomain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *caskinClient) UpdateDomain(ctx context.Context, in *Domain, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/caskin.Caskin/UpdateDomain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *caskinClient) GetAllDomain(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*DomainList, error) {
+	out := new(DomainList)
+	err := c.cc.Invoke(ctx, "/caskin.Caskin/GetAllDomain", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *caskinClient) AddSuperadminUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/caskin.Caskin/AddSuperadminUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *caskinClient) DeleteSuperadminUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*Empty, error) {
+	out := new(Empty)
+	err := c.cc.Invoke(ctx, "/caskin.Caskin/DeleteSuperadminUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *caskinClient) GetAllSuperadminUser(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*UserList, error) {
+	out := new(UserList)
+	err := c.cc.Invoke(ctx, "/caskin.Caskin/GetAllSuperadminUser", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CaskinServer is the server API for Caskin service.
+// All implementations must embed UnimplementedCaskinServer
+// for forward compatibility
+type CaskinServer interface {
+	GetAllUsersForRole(context.Context, *Empty) (*UsersForRoleList, error)
+	ModifyUsersForRole(context.Context, *UsersForRole) (*Empty, error)
+	GetAllRolesForUser(context.Context, *Empty) (*RolesForUserList, error)
+	ModifyRolesForUser(context.Context, *RolesForUser) (*Empty, error)
+	CreateRole(context.Context, *Role) (*Role, error)
+	RecoverRole(context.Context, *Role) (*Role, error)
+	DeleteRole(context.Context, *DeleteRoleRequest) (*Empty, error)
+	DeleteObject(context.Context, *DeleteObjectRequest) (*Empty, error)
+	CreateDomain(context.Context, *Domain) (*Domain, error)
+	RecoverDomain(context.Context, *Domain) (*Domain, error)
+	DeleteDomain(context.Context, *DeleteDomainRequest) (*Empty, error)
+	UpdateDomain(context.Context, *Domain) (*Empty, error)
+	GetAllDomain(context.Context, *Empty) (*DomainList, error)
+	AddSuperadminUser(context.Context, *User) (*Empty, error)
+	DeleteSuperadminUser(context.Context, *User) (*Empty, error)
+	GetAllSuperadminUser(context.Context, *Empty) (*UserList, error)
+	mustEmbedUnimplementedCaskinServer()
+}
+
+// UnimplementedCaskinServer must be embedded to have forward compatible implementations.
+type UnimplementedCaskinServer struct {
+}
+
+func (UnimplementedCaskinServer) GetAllUsersForRole(context.Context, *Empty) (*UsersForRoleList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllUsersForRole not implemented")
+}
+func (UnimplementedCaskinServer) ModifyUsersForRole(context.Context, *UsersForRole) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModifyUsersForRole not implemented")
+}
+func (UnimplementedCaskinServer) GetAllRolesForUser(context.Context, *Empty) (*RolesForUserList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllRolesForUser not implemented")
+}
+func (UnimplementedCaskinServer) ModifyRolesForUser(context.Context, *RolesForUser) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ModifyRolesForUser not implemented")
+}
+func (UnimplementedCaskinServer) CreateRole(context.Context, *Role) (*Role, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateRole not implemented")
+}
+func (UnimplementedCaskinServer) RecoverRole(context.Context, *Role) (*Role, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecoverRole not implemented")
+}
+func (UnimplementedCaskinServer) DeleteRole(context.Context, *DeleteRoleRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteRole not implemented")
+}
+func (UnimplementedCaskinServer) DeleteObject(context.Context, *DeleteObjectRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteObject not implemented")
+}
+func (UnimplementedCaskinServer) CreateDomain(context.Context, *Domain) (*Domain, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDomain not implemented")
+}
+func (UnimplementedCaskinServer) RecoverDomain(context.Context, *Domain) (*Domain, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecoverDomain not implemented")
+}
+func (UnimplementedCaskinServer) DeleteDomain(context.Context, *DeleteDomainRequest) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteDomain not implemented")
+}
+func (UnimplementedCaskinServer) UpdateDomain(context.Context, *Domain) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateDomain not implemented")
+}
+func (UnimplementedCaskinServer) GetAllDomain(context.Context, *Empty) (*DomainList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllDomain not implemented")
+}
+func (UnimplementedCaskinServer) AddSuperadminUser(context.Context, *User) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddSuperadminUser not implemented")
+}
+func (UnimplementedCaskinServer) DeleteSuperadminUser(context.Context, *User) (*Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteSuperadminUser not implemented")
+}
+func (UnimplementedCaskinServer) GetAllSuperadminUser(context.Context, *Empty) (*UserList, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAllSuperadminUser not implemented")
+}
+func (UnimplementedCaskinServer) mustEmbedUnimplementedCaskinServer() {}
+
+// UnsafeCaskinServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to CaskinServer will
+// result in compilation errors.
+type UnsafeCaskinServer interface {
+	mustEmbedUnimplementedCaskinServer()
+}
+
+func RegisterCaskinServer(s grpc.ServiceRegistrar, srv CaskinServer) {
+	s.RegisterService(&Caskin_ServiceDesc, srv)
+}
+
+func _Caskin_GetAllUsersForRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaskinServer).GetAllUsersForRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/caskin.Caskin/GetAllUsersForRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaskinServer).GetAllUsersForRole(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Caskin_ModifyUsersForRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UsersForRole)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaskinServer).ModifyUsersForRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/caskin.Caskin/ModifyUsersForRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaskinServer).ModifyUsersForRole(ctx, req.(*UsersForRole))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Caskin_GetAllRolesForUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaskinServer).GetAllRolesForUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/caskin.Caskin/GetAllRolesForUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaskinServer).GetAllRolesForUser(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Caskin_ModifyRolesForUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RolesForUser)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaskinServer).ModifyRolesForUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/caskin.Caskin/ModifyRolesForUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaskinServer).ModifyRolesForUser(ctx, req.(*RolesForUser))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Caskin_CreateRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Role)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaskinServer).CreateRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/caskin.Caskin/CreateRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaskinServer).CreateRole(ctx, req.(*Role))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Caskin_RecoverRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Role)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaskinServer).RecoverRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/caskin.Caskin/RecoverRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaskinServer).RecoverRole(ctx, req.(*Role))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Caskin_DeleteRole_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteRoleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaskinServer).DeleteRole(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/caskin.Caskin/DeleteRole",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaskinServer).DeleteRole(ctx, req.(*DeleteRoleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Caskin_DeleteObject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteObjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaskinServer).DeleteObject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/caskin.Caskin/DeleteObject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaskinServer).DeleteObject(ctx, req.(*DeleteObjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Caskin_CreateDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Domain)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaskinServer).CreateDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/caskin.Caskin/CreateDomain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaskinServer).CreateDomain(ctx, req.(*Domain))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Caskin_RecoverDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Domain)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaskinServer).RecoverDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/caskin.Caskin/RecoverDomain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaskinServer).RecoverDomain(ctx, req.(*Domain))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Caskin_DeleteDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDomainRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaskinServer).DeleteDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/caskin.Caskin/DeleteDomain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaskinServer).DeleteDomain(ctx, req.(*DeleteDomainRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Caskin_UpdateDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Domain)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaskinServer).UpdateDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/caskin.Caskin/UpdateDomain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaskinServer).UpdateDomain(ctx, req.(*Domain))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Caskin_GetAllDomain_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaskinServer).GetAllDomain(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/caskin.Caskin/GetAllDomain",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaskinServer).GetAllDomain(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Caskin_AddSuperadminUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(User)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaskinServer).AddSuperadminUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/caskin.Caskin/AddSuperadminUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaskinServer).AddSuperadminUser(ctx, req.(*User))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Caskin_DeleteSuperadminUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(User)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaskinServer).DeleteSuperadminUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/caskin.Caskin/DeleteSuperadminUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaskinServer).DeleteSuperadminUser(ctx, req.(*User))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Caskin_GetAllSuperadminUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(CaskinServer).GetAllSuperadminUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/caskin.Caskin/GetAllSuperadminUser",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(CaskinServer).GetAllSuperadminUser(ctx, req.(*Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Caskin_ServiceDesc is the grpc.ServiceDesc for Caskin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Caskin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "caskin.Caskin",
+	HandlerType: (*CaskinServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetAllUsersForRole",
+			Handler:    _Caskin_GetAllUsersForRole_Handler,
+		},
+		{
+			MethodName: "ModifyUsersForRole",
+			Handler:    _Caskin_ModifyUsersForRole_Handler,
+		},
+		{
+			MethodName: "GetAllRolesForUser",
+			Handler:    _Caskin_GetAllRolesForUser_Handler,
+		},
+		{
+			MethodName: "ModifyRolesForUser",
+			Handler:    _Caskin_ModifyRolesForUser_Handler,
+		},
+		{
+			MethodName: "CreateRole",
+			Handler:    _Caskin_CreateRole_Handler,
+		},
+		{
+			MethodName: "RecoverRole",
+			Handler:    _Caskin_RecoverRole_Handler,
+		},
+		{
+			MethodName: "DeleteRole",
+			Handler:    _Caskin_DeleteRole_Handler,
+		},
+		{
+			MethodName: "DeleteObject",
+			Handler:    _Caskin_DeleteObject_Handler,
+		},
+		{
+			MethodName: "CreateDomain",
+			Handler:    _Caskin_CreateDomain_Handler,
+		},
+		{
+			MethodName: "RecoverDomain",
+			Handler:    _Caskin_RecoverDomain_Handler,
+		},
+		{
+			MethodName: "DeleteDomain",
+			Handler:    _Caskin_DeleteDomain_Handler,
+		},
+		{
+			MethodName: "UpdateDomain",
+			Handler:    _Caskin_UpdateDomain_Handler,
+		},
+		{
+			MethodName: "GetAllDomain",
+			Handler:    _Caskin_GetAllDomain_Handler,
+		},
+		{
+			MethodName: "AddSuperadminUser",
+			Handler:    _Caskin_AddSuperadminUser_Handler,
+		},
+		{
+			MethodName: "DeleteSuperadminUser",
+			Handler:    _Caskin_DeleteSuperadminUser_Handler,
+		},
+		{
+			MethodName: "GetAllSuperadminUser",
+			Handler:    _Caskin_GetAllSuperadminUser_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "caskin.proto",
+}
@@ -0,0 +1,218 @@
+package caskingrpc
+
+import (
+	"context"
+
+	"github.com/awatercolorpen/caskin"
+	"google.golang.org/grpc"
+)
+
+// Client the Go client of the Server with caskin's entries,
+// the current user and domain are provided by ctx such as its outgoing metadata
+type Client struct {
+	client CaskinClient
+	codec  *codec
+}
+
+// NewClient build the Client, the EntryFactory decodes the response's entries
+func NewClient(conn grpc.ClientConnInterface, factory caskin.EntryFactory) *Client {
+	return &Client{
+		client: NewCaskinClient(conn),
+		codec:  &codec{factory: factory},
+	}
+}
+
+func (c *Client) GetAllUsersForRole(ctx context.Context) ([]*caskin.UsersForRole, error) {
+	out, err := c.client.GetAllUsersForRole(ctx, &Empty{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	var list []*caskin.UsersForRole
+	for _, v := range out.GetItems() {
+		ur, err := c.codec.decodeUsersForRole(v)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, ur)
+	}
+	return list, nil
+}
+
+func (c *Client) ModifyUsersForRole(ctx context.Context, ur *caskin.UsersForRole) error {
+	in, err := c.codec.encodeUsersForRole(ur)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.ModifyUsersForRole(ctx, in)
+	return fromStatus(err)
+}
+
+func (c *Client) GetAllRolesForUser(ctx context.Context) ([]*caskin.RolesForUser, error) {
+	out, err := c.client.GetAllRolesForUser(ctx, &Empty{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	var list []*caskin.RolesForUser
+	for _, v := range out.GetItems() {
+		ru, err := c.codec.decodeRolesForUser(v)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, ru)
+	}
+	return list, nil
+}
+
+func (c *Client) ModifyRolesForUser(ctx context.Context, ru *caskin.RolesForUser) error {
+	in, err := c.codec.encodeRolesForUser(ru)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.ModifyRolesForUser(ctx, in)
+	return fromStatus(err)
+}
+
+// CreateRole create the role and update it with the created one
+func (c *Client) CreateRole(ctx context.Context, role caskin.Role) error {
+	in, err := c.codec.encodeRole(role)
+	if err != nil {
+		return err
+	}
+	out, err := c.client.CreateRole(ctx, in)
+	if err != nil {
+		return fromStatus(err)
+	}
+	return c.codec.decode(out.GetCode(), out.GetJson(), role)
+}
+
+// RecoverRole recover the role and update it with the recovered one
+func (c *Client) RecoverRole(ctx context.Context, role caskin.Role) error {
+	in, err := c.codec.encodeRole(role)
+	if err != nil {
+		return err
+	}
+	out, err := c.client.RecoverRole(ctx, in)
+	if err != nil {
+		return fromStatus(err)
+	}
+	return c.codec.decode(out.GetCode(), out.GetJson(), role)
+}
+
+func (c *Client) DeleteRole(ctx context.Context, role caskin.Role, option ...caskin.DeleteOption) error {
+	in, err := c.codec.encodeRole(role)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.DeleteRole(ctx, &DeleteRoleRequest{Role: in, Option: optionString(option)})
+	return fromStatus(err)
+}
+
+func (c *Client) DeleteObject(ctx context.Context, object caskin.Object, option ...caskin.DeleteOption) error {
+	in, err := c.codec.encodeObject(object)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.DeleteObject(ctx, &DeleteObjectRequest{Object: in, Option: optionString(option)})
+	return fromStatus(err)
+}
+
+// CreateDomain create the domain and update it with the created one
+func (c *Client) CreateDomain(ctx context.Context, domain caskin.Domain) error {
+	in, err := c.codec.encodeDomain(domain)
+	if err != nil {
+		return err
+	}
+	out, err := c.client.CreateDomain(ctx, in)
+	if err != nil {
+		return fromStatus(err)
+	}
+	return c.codec.decode(out.GetCode(), out.GetJson(), domain)
+}
+
+// RecoverDomain recover the domain and update it with the recovered one
+func (c *Client) RecoverDomain(ctx context.Context, domain caskin.Domain) error {
+	in, err := c.codec.encodeDomain(domain)
+	if err != nil {
+		return err
+	}
+	out, err := c.client.RecoverDomain(ctx, in)
+	if err != nil {
+		return fromStatus(err)
+	}
+	return c.codec.decode(out.GetCode(), out.GetJson(), domain)
+}
+
+func (c *Client) DeleteDomain(ctx context.Context, domain caskin.Domain, option ...caskin.DeleteOption) error {
+	in, err := c.codec.encodeDomain(domain)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.DeleteDomain(ctx, &DeleteDomainRequest{Domain: in, Option: optionString(option)})
+	return fromStatus(err)
+}
+
+func (c *Client) UpdateDomain(ctx context.Context, domain caskin.Domain) error {
+	in, err := c.codec.encodeDomain(domain)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.UpdateDomain(ctx, in)
+	return fromStatus(err)
+}
+
+func (c *Client) GetAllDomain(ctx context.Context) ([]caskin.Domain, error) {
+	out, err := c.client.GetAllDomain(ctx, &Empty{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	var list []caskin.Domain
+	for _, v := range out.GetItems() {
+		domain, err := c.codec.decodeDomain(v)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, domain)
+	}
+	return list, nil
+}
+
+func (c *Client) AddSuperadminUser(ctx context.Context, user caskin.User) error {
+	in, err := c.codec.encodeUser(user)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.AddSuperadminUser(ctx, in)
+	return fromStatus(err)
+}
+
+func (c *Client) DeleteSuperadminUser(ctx context.Context, user caskin.User) error {
+	in, err := c.codec.encodeUser(user)
+	if err != nil {
+		return err
+	}
+	_, err = c.client.DeleteSuperadminUser(ctx, in)
+	return fromStatus(err)
+}
+
+func (c *Client) GetAllSuperadminUser(ctx context.Context) ([]caskin.User, error) {
+	out, err := c.client.GetAllSuperadminUser(ctx, &Empty{})
+	if err != nil {
+		return nil, fromStatus(err)
+	}
+	var list []caskin.User
+	for _, v := range out.GetItems() {
+		user, err := c.codec.decodeUser(v)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, user)
+	}
+	return list, nil
+}
+
+func optionString(option []caskin.DeleteOption) string {
+	if len(option) == 0 {
+		return ""
+	}
+	return string(option[0])
+}
@@ -0,0 +1,152 @@
+package caskingrpc
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/awatercolorpen/caskin"
+)
+
+// codec convert between caskin's entries and the messages by the EntryFactory
+type codec struct {
+	factory caskin.EntryFactory
+}
+
+func (c *codec) encode(one caskin.Entry) (string, []byte, error) {
+	data, err := json.Marshal(one)
+	if err != nil {
+		return "", nil, err
+	}
+	return one.Encode(), data, nil
+}
+
+// decode unmarshal the JSON payload into the entry, then decode the code which wins over the payload's id
+func (c *codec) decode(code string, data []byte, one caskin.Entry) error {
+	if len(data) != 0 {
+		if err := json.Unmarshal(data, one); err != nil {
+			return invalidRequest(err)
+		}
+	}
+	if code != "" {
+		if err := one.Decode(code); err != nil {
+			return invalidRequest(err)
+		}
+	}
+	return nil
+}
+
+func (c *codec) encodeUser(user caskin.User) (*User, error) {
+	code, data, err := c.encode(user)
+	return &User{Code: code, Json: data}, err
+}
+
+func (c *codec) decodeUser(m *User) (caskin.User, error) {
+	user := c.factory.NewUser()
+	return user, c.decode(m.GetCode(), m.GetJson(), user)
+}
+
+func (c *codec) encodeRole(role caskin.Role) (*Role, error) {
+	code, data, err := c.encode(role)
+	return &Role{Code: code, Json: data}, err
+}
+
+func (c *codec) decodeRole(m *Role) (caskin.Role, error) {
+	role := c.factory.NewRole()
+	return role, c.decode(m.GetCode(), m.GetJson(), role)
+}
+
+func (c *codec) encodeObject(object caskin.Object) (*Object, error) {
+	code, data, err := c.encode(object)
+	return &Object{Code: code, Json: data}, err
+}
+
+func (c *codec) decodeObject(m *Object) (caskin.Object, error) {
+	object := c.factory.NewObject()
+	return object, c.decode(m.GetCode(), m.GetJson(), object)
+}
+
+func (c *codec) encodeDomain(domain caskin.Domain) (*Domain, error) {
+	code, data, err := c.encode(domain)
+	return &Domain{Code: code, Json: data}, err
+}
+
+func (c *codec) decodeDomain(m *Domain) (caskin.Domain, error) {
+	domain := c.factory.NewDomain()
+	return domain, c.decode(m.GetCode(), m.GetJson(), domain)
+}
+
+func (c *codec) encodeUsersForRole(ur *caskin.UsersForRole) (*UsersForRole, error) {
+	role, err := c.encodeRole(ur.Role)
+	if err != nil {
+		return nil, err
+	}
+	m := &UsersForRole{Role: role}
+	for _, v := range ur.Users {
+		user, err := c.encodeUser(v)
+		if err != nil {
+			return nil, err
+		}
+		m.Users = append(m.Users, user)
+	}
+	return m, nil
+}
+
+func (c *codec) decodeUsersForRole(m *UsersForRole) (*caskin.UsersForRole, error) {
+	role, err := c.decodeRole(m.GetRole())
+	if err != nil {
+		return nil, err
+	}
+	ur := &caskin.UsersForRole{Role: role}
+	for _, v := range m.GetUsers() {
+		user, err := c.decodeUser(v)
+		if err != nil {
+			return nil, err
+		}
+		ur.Users = append(ur.Users, user)
+	}
+	return ur, nil
+}
+
+func (c *codec) encodeRolesForUser(ru *caskin.RolesForUser) (*RolesForUser, error) {
+	user, err := c.encodeUser(ru.User)
+	if err != nil {
+		return nil, err
+	}
+	m := &RolesForUser{User: user}
+	for _, v := range ru.Roles {
+		role, err := c.encodeRole(v)
+		if err != nil {
+			return nil, err
+		}
+		m.Roles = append(m.Roles, role)
+	}
+	return m, nil
+}
+
+func (c *codec) decodeRolesForUser(m *RolesForUser) (*caskin.RolesForUser, error) {
+	user, err := c.decodeUser(m.GetUser())
+	if err != nil {
+		return nil, err
+	}
+	ru := &caskin.RolesForUser{User: user}
+	for _, v := range m.GetRoles() {
+		role, err := c.decodeRole(v)
+		if err != nil {
+			return nil, err
+		}
+		ru.Roles = append(ru.Roles, role)
+	}
+	return ru, nil
+}
+
+// deleteOption get the DeleteOption of the request's option
+func deleteOption(option string) []caskin.DeleteOption {
+	if option != "" {
+		return []caskin.DeleteOption{caskin.DeleteOption(option)}
+	}
+	return nil
+}
+
+func invalidRequest(err error) error {
+	return fmt.Errorf("%w: %v", caskin.ErrInvalidRequest, err)
+}
@@ -0,0 +1,45 @@
+// Package caskingrpc exposes caskin's executor operations as a gRPC service,
+// and protects methods by caskin's objects with the Authorizer interceptors.
+// entries are transported as their encoded code and opaque JSON payload decoded by the EntryFactory
+package caskingrpc
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative caskin.proto
+
+import (
+	"context"
+
+	"github.com/awatercolorpen/caskin"
+)
+
+// Executor the operations of caskin's executor served by the Server,
+// caskin's executor built by GetExecutor implements it
+type Executor interface {
+	GetAllUsersForRole() ([]*caskin.UsersForRole, error)
+	ModifyUsersForRole(*caskin.UsersForRole) error
+	GetAllRolesForUser() ([]*caskin.RolesForUser, error)
+	ModifyRolesForUser(*caskin.RolesForUser) error
+
+	CreateRole(caskin.Role) error
+	RecoverRole(caskin.Role) error
+	DeleteRole(caskin.Role, ...caskin.DeleteOption) error
+
+	DeleteObject(caskin.Object, ...caskin.DeleteOption) error
+
+	CreateDomain(caskin.Domain) error
+	RecoverDomain(caskin.Domain) error
+	DeleteDomain(caskin.Domain, ...caskin.DeleteOption) error
+	UpdateDomain(caskin.Domain) error
+	GetAllDomain() ([]caskin.Domain, error)
+
+	AddSuperadminUser(caskin.User) error
+	DeleteSuperadminUser(caskin.User) error
+	GetAllSuperadminUser() ([]caskin.User, error)
+}
+
+// ExecutorBuilder build the Executor for the current user, such as
+//
+//	func(p caskin.CurrentUserProvider) caskingrpc.Executor { return m.GetExecutor(p) }
+type ExecutorBuilder func(caskin.CurrentUserProvider) Executor
+
+// Authenticator authenticate the call by its context, such as its metadata, and provide its current user and domain
+type Authenticator func(context.Context) (caskin.CurrentUserProvider, error)
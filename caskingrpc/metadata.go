@@ -0,0 +1,55 @@
+package caskingrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/awatercolorpen/caskin"
+	"google.golang.org/grpc/metadata"
+)
+
+const (
+	// MetadataUser the metadata key of current user's code
+	MetadataUser = "caskin-user"
+	// MetadataDomain the metadata key of current domain's code
+	MetadataDomain = "caskin-domain"
+)
+
+// metadataProvider the CurrentUserProvider of the user and domain decoded from metadata
+type metadataProvider struct {
+	user   caskin.User
+	domain caskin.Domain
+}
+
+func (m *metadataProvider) Get() (caskin.User, caskin.Domain, error) {
+	return m.user, m.domain, nil
+}
+
+// MetadataAuthenticator the Authenticator decoding current user and domain from the incoming metadata's codes by the EntryFactory,
+// it is for trusted callers such as an authenticating gateway which sets the metadata
+func MetadataAuthenticator(factory caskin.EntryFactory) Authenticator {
+	return func(ctx context.Context) (caskin.CurrentUserProvider, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		user, domain := factory.NewUser(), factory.NewDomain()
+		if err := decodeMetadata(md, MetadataUser, user); err != nil {
+			return nil, err
+		}
+		if err := decodeMetadata(md, MetadataDomain, domain); err != nil {
+			return nil, err
+		}
+		return &metadataProvider{user: user, domain: domain}, nil
+	}
+}
+
+// WithCurrentUser append current user and domain's codes to the outgoing metadata for MetadataAuthenticator
+func WithCurrentUser(ctx context.Context, user caskin.User, domain caskin.Domain) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, MetadataUser, user.Encode(), MetadataDomain, domain.Encode())
+}
+
+func decodeMetadata(md metadata.MD, key string, one caskin.Entry) error {
+	values := md.Get(key)
+	if len(values) == 0 {
+		return fmt.Errorf("missing metadata %v", key)
+	}
+	return one.Decode(values[0])
+}
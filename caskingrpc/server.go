@@ -0,0 +1,265 @@
+package caskingrpc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/awatercolorpen/caskin"
+)
+
+// Server the CaskinServer over caskin's executor, register it by RegisterCaskinServer
+type Server struct {
+	UnimplementedCaskinServer
+	builder ExecutorBuilder
+	auth    Authenticator
+	codec   *codec
+}
+
+// NewServer build the Server
+// 1. authenticate every call by the Authenticator
+// 2. build the Executor for the call's current user by the ExecutorBuilder
+// 3. decode request's entries by the EntryFactory
+func NewServer(builder ExecutorBuilder, factory caskin.EntryFactory, auth Authenticator) *Server {
+	return &Server{
+		builder: builder,
+		auth:    auth,
+		codec:   &codec{factory: factory},
+	}
+}
+
+// call run fn with the Executor of the call's current user, and convert its error to gRPC status
+func (s *Server) call(ctx context.Context, fn func(Executor) error) error {
+	provider, err := s.auth(ctx)
+	if err != nil {
+		return toStatus(fmt.Errorf("%w: %v", caskin.ErrUnauthorized, err))
+	}
+	return toStatus(fn(s.builder(provider)))
+}
+
+func (s *Server) GetAllUsersForRole(ctx context.Context, _ *Empty) (*UsersForRoleList, error) {
+	out := &UsersForRoleList{}
+	err := s.call(ctx, func(e Executor) error {
+		list, err := e.GetAllUsersForRole()
+		if err != nil {
+			return err
+		}
+		for _, v := range list {
+			ur, err := s.codec.encodeUsersForRole(v)
+			if err != nil {
+				return err
+			}
+			out.Items = append(out.Items, ur)
+		}
+		return nil
+	})
+	return out, err
+}
+
+func (s *Server) ModifyUsersForRole(ctx context.Context, in *UsersForRole) (*Empty, error) {
+	err := s.call(ctx, func(e Executor) error {
+		ur, err := s.codec.decodeUsersForRole(in)
+		if err != nil {
+			return err
+		}
+		return e.ModifyUsersForRole(ur)
+	})
+	return &Empty{}, err
+}
+
+func (s *Server) GetAllRolesForUser(ctx context.Context, _ *Empty) (*RolesForUserList, error) {
+	out := &RolesForUserList{}
+	err := s.call(ctx, func(e Executor) error {
+		list, err := e.GetAllRolesForUser()
+		if err != nil {
+			return err
+		}
+		for _, v := range list {
+			ru, err := s.codec.encodeRolesForUser(v)
+			if err != nil {
+				return err
+			}
+			out.Items = append(out.Items, ru)
+		}
+		return nil
+	})
+	return out, err
+}
+
+func (s *Server) ModifyRolesForUser(ctx context.Context, in *RolesForUser) (*Empty, error) {
+	err := s.call(ctx, func(e Executor) error {
+		ru, err := s.codec.decodeRolesForUser(in)
+		if err != nil {
+			return err
+		}
+		return e.ModifyRolesForUser(ru)
+	})
+	return &Empty{}, err
+}
+
+func (s *Server) CreateRole(ctx context.Context, in *Role) (*Role, error) {
+	var out *Role
+	err := s.call(ctx, func(e Executor) error {
+		role, err := s.codec.decodeRole(in)
+		if err != nil {
+			return err
+		}
+		if err := e.CreateRole(role); err != nil {
+			return err
+		}
+		out, err = s.codec.encodeRole(role)
+		return err
+	})
+	return out, err
+}
+
+func (s *Server) RecoverRole(ctx context.Context, in *Role) (*Role, error) {
+	var out *Role
+	err := s.call(ctx, func(e Executor) error {
+		role, err := s.codec.decodeRole(in)
+		if err != nil {
+			return err
+		}
+		if err := e.RecoverRole(role); err != nil {
+			return err
+		}
+		out, err = s.codec.encodeRole(role)
+		return err
+	})
+	return out, err
+}
+
+func (s *Server) DeleteRole(ctx context.Context, in *DeleteRoleRequest) (*Empty, error) {
+	err := s.call(ctx, func(e Executor) error {
+		role, err := s.codec.decodeRole(in.GetRole())
+		if err != nil {
+			return err
+		}
+		return e.DeleteRole(role, deleteOption(in.GetOption())...)
+	})
+	return &Empty{}, err
+}
+
+func (s *Server) DeleteObject(ctx context.Context, in *DeleteObjectRequest) (*Empty, error) {
+	err := s.call(ctx, func(e Executor) error {
+		object, err := s.codec.decodeObject(in.GetObject())
+		if err != nil {
+			return err
+		}
+		return e.DeleteObject(object, deleteOption(in.GetOption())...)
+	})
+	return &Empty{}, err
+}
+
+func (s *Server) CreateDomain(ctx context.Context, in *Domain) (*Domain, error) {
+	var out *Domain
+	err := s.call(ctx, func(e Executor) error {
+		domain, err := s.codec.decodeDomain(in)
+		if err != nil {
+			return err
+		}
+		if err := e.CreateDomain(domain); err != nil {
+			return err
+		}
+		out, err = s.codec.encodeDomain(domain)
+		return err
+	})
+	return out, err
+}
+
+func (s *Server) RecoverDomain(ctx context.Context, in *Domain) (*Domain, error) {
+	var out *Domain
+	err := s.call(ctx, func(e Executor) error {
+		domain, err := s.codec.decodeDomain(in)
+		if err != nil {
+			return err
+		}
+		if err := e.RecoverDomain(domain); err != nil {
+			return err
+		}
+		out, err = s.codec.encodeDomain(domain)
+		return err
+	})
+	return out, err
+}
+
+func (s *Server) DeleteDomain(ctx context.Context, in *DeleteDomainRequest) (*Empty, error) {
+	err := s.call(ctx, func(e Executor) error {
+		domain, err := s.codec.decodeDomain(in.GetDomain())
+		if err != nil {
+			return err
+		}
+		return e.DeleteDomain(domain, deleteOption(in.GetOption())...)
+	})
+	return &Empty{}, err
+}
+
+func (s *Server) UpdateDomain(ctx context.Context, in *Domain) (*Empty, error) {
+	err := s.call(ctx, func(e Executor) error {
+		domain, err := s.codec.decodeDomain(in)
+		if err != nil {
+			return err
+		}
+		return e.UpdateDomain(domain)
+	})
+	return &Empty{}, err
+}
+
+func (s *Server) GetAllDomain(ctx context.Context, _ *Empty) (*DomainList, error) {
+	out := &DomainList{}
+	err := s.call(ctx, func(e Executor) error {
+		list, err := e.GetAllDomain()
+		if err != nil {
+			return err
+		}
+		for _, v := range list {
+			domain, err := s.codec.encodeDomain(v)
+			if err != nil {
+				return err
+			}
+			out.Items = append(out.Items, domain)
+		}
+		return nil
+	})
+	return out, err
+}
+
+func (s *Server) AddSuperadminUser(ctx context.Context, in *User) (*Empty, error) {
+	err := s.call(ctx, func(e Executor) error {
+		user, err := s.codec.decodeUser(in)
+		if err != nil {
+			return err
+		}
+		return e.AddSuperadminUser(user)
+	})
+	return &Empty{}, err
+}
+
+func (s *Server) DeleteSuperadminUser(ctx context.Context, in *User) (*Empty, error) {
+	err := s.call(ctx, func(e Executor) error {
+		user, err := s.codec.decodeUser(in)
+		if err != nil {
+			return err
+		}
+		return e.DeleteSuperadminUser(user)
+	})
+	return &Empty{}, err
+}
+
+func (s *Server) GetAllSuperadminUser(ctx context.Context, _ *Empty) (*UserList, error) {
+	out := &UserList{}
+	err := s.call(ctx, func(e Executor) error {
+		list, err := e.GetAllSuperadminUser()
+		if err != nil {
+			return err
+		}
+		for _, v := range list {
+			user, err := s.codec.encodeUser(v)
+			if err != nil {
+				return err
+			}
+			out.Items = append(out.Items, user)
+		}
+		return nil
+	})
+	return out, err
+}
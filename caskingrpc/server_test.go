@@ -0,0 +1,88 @@
+package caskingrpc_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/caskingrpc"
+	"github.com/awatercolorpen/caskin/example"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// roleExecutor the Executor which creates roles and can't delete any of them
+type roleExecutor struct {
+	caskingrpc.Executor
+	user    caskin.User
+	created []caskin.Role
+}
+
+func (r *roleExecutor) CreateRole(role caskin.Role) error {
+	role.SetID(uint64(len(r.created) + 1))
+	r.created = append(r.created, role)
+	return nil
+}
+
+func (r *roleExecutor) DeleteRole(role caskin.Role, _ ...caskin.DeleteOption) error {
+	return &caskin.PermissionError{User: r.user, Entry: role.Encode(), Action: caskin.Write}
+}
+
+// dial serve the server in memory and dial it
+func dial(t *testing.T, opt ...grpc.ServerOption) (*grpc.ClientConn, *roleExecutor) {
+	e := &roleExecutor{}
+	builder := func(p caskin.CurrentUserProvider) caskingrpc.Executor {
+		e.user, _, _ = p.Get()
+		return e
+	}
+	factory := example.NewEntryFactory()
+	server := grpc.NewServer(opt...)
+	caskingrpc.RegisterCaskinServer(server, caskingrpc.NewServer(builder, factory, caskingrpc.MetadataAuthenticator(factory)))
+
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.Dial("bufconn",
+		grpc.WithInsecure(),
+		grpc.WithContextDialer(func(context.Context, string) (net.Conn, error) { return listener.Dial() }),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn, e
+}
+
+func TestServer(t *testing.T) {
+	conn, e := dial(t)
+	client := caskingrpc.NewClient(conn, example.NewEntryFactory())
+	ctx := caskingrpc.WithCurrentUser(context.Background(), &example.User{ID: 2}, &example.Domain{ID: 1})
+
+	role := &example.Role{Name: "admin"}
+	if err := client.CreateRole(ctx, role); err != nil {
+		t.Fatal(err)
+	}
+	if role.ID != 1 || len(e.created) != 1 || e.created[0].(*example.Role).Name != "admin" || e.user.GetID() != 2 {
+		t.Fatal(role, e.created, e.user)
+	}
+
+	err := client.DeleteRole(ctx, &example.Role{ID: 1})
+	var se *caskingrpc.StatusError
+	if !errors.As(err, &se) || !errors.Is(err, caskin.ErrNoWritePermission) || status.Code(err) != codes.PermissionDenied {
+		t.Fatal(err)
+	}
+	if se.Code != caskin.CodeNoWritePermission || se.Metadata["entry"] != "role_1" || se.Metadata["user"] != "user_2" {
+		t.Fatal(se.Code, se.Metadata)
+	}
+
+	// there is no current user in the metadata
+	err = client.CreateRole(context.Background(), &example.Role{Name: "member"})
+	if !errors.Is(err, caskin.ErrUnauthorized) || status.Code(err) != codes.Unauthenticated {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,125 @@
+package caskingrpc
+
+import (
+	"errors"
+
+	"github.com/awatercolorpen/caskin"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorDomain the domain of the ErrorInfo detail carrying caskin's ErrorCode
+const ErrorDomain = "caskin"
+
+// grpcCodes caskin's ErrorCode to gRPC code
+var grpcCodes = map[caskin.ErrorCode]codes.Code{
+	caskin.CodeNil:                      codes.InvalidArgument,
+	caskin.CodeEmptyID:                  codes.InvalidArgument,
+	caskin.CodeAlreadyExists:            codes.AlreadyExists,
+	caskin.CodeNotExists:                codes.NotFound,
+	caskin.CodeCrossDomain:              codes.PermissionDenied,
+	caskin.CodeInvalidEntryType:         codes.InvalidArgument,
+	caskin.CodeHasDependents:            codes.FailedPrecondition,
+	caskin.CodeInvalidDeleteOption:      codes.InvalidArgument,
+	caskin.CodeNoReadPermission:         codes.PermissionDenied,
+	caskin.CodeNoWritePermission:        codes.PermissionDenied,
+	caskin.CodeIsNotSuperAdmin:          codes.PermissionDenied,
+	caskin.CodeSuperAdminIsNotEnabled:   codes.Unimplemented,
+	caskin.CodeInvalidSuperadmin:        codes.Internal,
+	caskin.CodeDomainAdminIsNotEnabled:  codes.Unimplemented,
+	caskin.CodeDomainAdminRoleNotExists: codes.FailedPrecondition,
+	caskin.CodeInvalidDomainAdmin:       codes.Internal,
+	caskin.CodeInvalidCode:              codes.InvalidArgument,
+	caskin.CodeInvalidEntryCodec:        codes.Internal,
+	caskin.CodeEntryCodecCollision:      codes.Internal,
+	caskin.CodeInvalidDomainSpec:        codes.InvalidArgument,
+	caskin.CodeTrashIsNotSupported:      codes.Unimplemented,
+	caskin.CodeInvalidRequest:           codes.InvalidArgument,
+	caskin.CodeUnauthorized:             codes.Unauthenticated,
+	caskin.CodeForbidden:                codes.PermissionDenied,
+}
+
+// GetGRPCCode get the error's gRPC code, it is OK for nil and Unknown for not caskin's error
+func GetGRPCCode(err error) codes.Code {
+	if err == nil {
+		return codes.OK
+	}
+	if c, ok := grpcCodes[caskin.GetErrorCode(err)]; ok {
+		return c
+	}
+	return codes.Unknown
+}
+
+// StatusError the error from the Server with its caskin's ErrorCode,
+// it unwraps to the sentinel error of the code so errors.Is works at client side
+type StatusError struct {
+	Code    caskin.ErrorCode
+	Message string
+	// structured details such as the user, domain, entry and action of PermissionError
+	Metadata map[string]string
+	status   *status.Status
+}
+
+func (s *StatusError) Error() string {
+	return s.Message
+}
+
+func (s *StatusError) Unwrap() error {
+	return caskin.GetErrorByCode(s.Code)
+}
+
+// GRPCStatus the origin status, status.FromError and status.Code work with it
+func (s *StatusError) GRPCStatus() *status.Status {
+	return s.status
+}
+
+// toStatus convert the error to gRPC status with the ErrorInfo detail of its code
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	c, code := GetGRPCCode(err), caskin.GetErrorCode(err)
+	info := &errdetails.ErrorInfo{Reason: string(code), Domain: ErrorDomain}
+	var pe *caskin.PermissionError
+	if errors.As(err, &pe) {
+		info.Metadata = map[string]string{"entry": pe.Entry, "action": string(pe.Action)}
+		if pe.User != nil {
+			info.Metadata["user"] = pe.User.Encode()
+		}
+		if pe.Domain != nil {
+			info.Metadata["domain"] = pe.Domain.Encode()
+		}
+	}
+
+	st := status.New(c, err.Error())
+	if detailed, e := st.WithDetails(info); e == nil {
+		st = detailed
+	}
+	return st.Err()
+}
+
+// fromStatus convert the gRPC status with the ErrorInfo detail to StatusError,
+// it returns the error as it is if there is no such detail
+func fromStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return err
+	}
+	for _, v := range st.Details() {
+		if info, ok := v.(*errdetails.ErrorInfo); ok && info.GetDomain() == ErrorDomain {
+			return &StatusError{
+				Code:     caskin.ErrorCode(info.GetReason()),
+				Message:  st.Message(),
+				Metadata: info.GetMetadata(),
+				status:   st,
+			}
+		}
+	}
+	return err
+}
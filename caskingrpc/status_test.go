@@ -0,0 +1,30 @@
+package caskingrpc_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/caskingrpc"
+	"google.golang.org/grpc/codes"
+)
+
+func TestGetGRPCCode(t *testing.T) {
+	for err, code := range map[error]codes.Code{
+		nil:                            codes.OK,
+		caskin.ErrEmptyID:              codes.InvalidArgument,
+		caskin.ErrInvalidDeleteOption:  codes.InvalidArgument,
+		caskin.ErrHasDependents:        codes.FailedPrecondition,
+		caskin.ErrIsNotSuperAdmin:      codes.PermissionDenied,
+		caskin.ErrTrashIsNotSupported:  codes.Unimplemented,
+		caskin.ErrInvalidEntryCodec:    codes.Internal,
+		&caskin.NotFoundError{ID: 1}:   codes.NotFound,
+		&caskin.ConflictError{ID: 1}:   codes.AlreadyExists,
+		&caskin.PermissionError{}:      codes.PermissionDenied,
+		fmt.Errorf("database is gone"): codes.Unknown,
+	} {
+		if c := caskingrpc.GetGRPCCode(err); c != code {
+			t.Fatal(err, c)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package caskinhttp
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/awatercolorpen/caskin"
+)
+
+// Authorizer the middleware protecting routes by caskin's objects,
+// route patterns are matched the same as http.ServeMux
+type Authorizer struct {
+	enforcer caskin.Enforcer
+	auth     Authenticator
+	unmapped caskin.Unmapped
+	mux      *http.ServeMux
+	// pattern to method to permission, empty method for any method
+	routes map[string]map[string]*caskin.Permission
+}
+
+// NewAuthorizer build the Authorizer
+func NewAuthorizer(enforcer caskin.Enforcer, auth Authenticator, unmapped caskin.Unmapped) *Authorizer {
+	return &Authorizer{
+		enforcer: enforcer,
+		auth:     auth,
+		unmapped: unmapped,
+		mux:      http.NewServeMux(),
+		routes:   map[string]map[string]*caskin.Permission{},
+	}
+}
+
+// Handle register the route pattern and method requiring the action on the object, empty method for any method
+func (a *Authorizer) Handle(method, pattern string, object caskin.Object, action caskin.Action) {
+	if _, ok := a.routes[pattern]; !ok {
+		a.routes[pattern] = map[string]*caskin.Permission{}
+		a.mux.Handle(pattern, http.NotFoundHandler())
+	}
+	a.routes[pattern][method] = &caskin.Permission{Object: object, Action: action}
+}
+
+// Middleware authorize the request before next handler
+// 1. find the permission of the request's route, deal with unmapped route by caskin.Unmapped
+// 2. reject with 405 if the route is mapped but not for the request's method
+// 3. authenticate the request by the Authenticator to get current user and domain
+// 4. enforce current user's action on the object in current domain, reject with 403 if it is not allowed
+func (a *Authorizer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		permission, mapped := a.permission(r)
+		if !mapped {
+			if a.unmapped == caskin.UnmappedAllow {
+				next.ServeHTTP(w, r)
+				return
+			}
+			writeError(w, fmt.Errorf("%w: %v %v is not mapped", caskin.ErrForbidden, r.Method, r.URL.Path))
+			return
+		}
+		if permission == nil {
+			methodNotAllowed(w, r)
+			return
+		}
+
+		provider, err := a.auth(r)
+		if err != nil {
+			writeError(w, fmt.Errorf("%w: %v", caskin.ErrUnauthorized, err))
+			return
+		}
+		if err := permission.Authorize(a.enforcer, provider); err != nil {
+			writeError(w, err)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// permission get the permission of the request's method, or of any method, mapped is false if the route is not mapped,
+// and the permission is nil if the route is mapped but neither the method nor any method is registered
+func (a *Authorizer) permission(r *http.Request) (*caskin.Permission, bool) {
+	_, pattern := a.mux.Handler(r)
+	methods, ok := a.routes[pattern]
+	if !ok {
+		return nil, false
+	}
+	if p, ok := methods[r.Method]; ok {
+		return p, true
+	}
+	return methods[""], true
+}
@@ -0,0 +1,54 @@
+package caskinhttp_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/caskinhttp"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+// readEnforcer allow user 1 to read every object only
+type readEnforcer struct{}
+
+func (readEnforcer) Enforce(user caskin.User, _ caskin.Object, _ caskin.Domain, action caskin.Action) (bool, error) {
+	return user.GetID() == 1 && action == caskin.Read, nil
+}
+
+func TestAuthorizer(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusAccepted) })
+	for _, unmapped := range []caskin.Unmapped{caskin.UnmappedAllow, caskin.UnmappedDeny} {
+		a := caskinhttp.NewAuthorizer(readEnforcer{}, authenticate, unmapped)
+		a.Handle(http.MethodGet, "/items/", &example.Object{ID: 1}, caskin.Read)
+		a.Handle(http.MethodPut, "/items/", &example.Object{ID: 1}, caskin.Write)
+		h := a.Middleware(next)
+
+		if w := serve(h, http.MethodGet, "/items/1", ""); w.Code != http.StatusAccepted {
+			t.Fatal(unmapped, w.Code)
+		}
+		if w := serve(h, http.MethodPut, "/items/1", ""); w.Code != http.StatusForbidden || errorCode(t, w) != caskin.CodeNoWritePermission {
+			t.Fatal(unmapped, w.Code)
+		}
+		// the route is mapped, the method which is not registered never passes as unmapped
+		if w := serve(h, http.MethodPost, "/items/1", ""); w.Code != http.StatusMethodNotAllowed || errorCode(t, w) != caskinhttp.CodeMethodNotAllowed {
+			t.Fatal(unmapped, w.Code)
+		}
+
+		r := httptest.NewRequest(http.MethodGet, "/items/1", nil)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		if w.Code != http.StatusUnauthorized {
+			t.Fatal(unmapped, w.Code)
+		}
+
+		w = serve(h, http.MethodPost, "/other", "")
+		if unmapped == caskin.UnmappedAllow && w.Code != http.StatusAccepted {
+			t.Fatal(unmapped, w.Code)
+		}
+		if unmapped == caskin.UnmappedDeny && (w.Code != http.StatusForbidden || errorCode(t, w) != caskin.CodeForbidden) {
+			t.Fatal(unmapped, w.Code)
+		}
+	}
+}
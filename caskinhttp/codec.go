@@ -0,0 +1,139 @@
+package caskinhttp
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/awatercolorpen/caskin"
+)
+
+// CodeMethodNotAllowed the code of the request whose method is not served
+const CodeMethodNotAllowed caskin.ErrorCode = "method_not_allowed"
+
+type errorJSON struct {
+	Code    caskin.ErrorCode `json:"code"`
+	Message string           `json:"message"`
+}
+
+type usersForRoleJSON struct {
+	Role  json.RawMessage   `json:"role"`
+	Users []json.RawMessage `json:"users"`
+}
+
+type rolesForUserJSON struct {
+	User  json.RawMessage   `json:"user"`
+	Roles []json.RawMessage `json:"roles"`
+}
+
+// decoder decode request's JSON body into the EntryFactory's entries
+type decoder struct {
+	factory caskin.EntryFactory
+}
+
+func (d *decoder) body(r *http.Request, v interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return invalidRequest(err)
+	}
+	return nil
+}
+
+func (d *decoder) entry(data json.RawMessage, one interface{}) error {
+	if err := json.Unmarshal(data, one); err != nil {
+		return invalidRequest(err)
+	}
+	return nil
+}
+
+func (d *decoder) role(r *http.Request) (caskin.Role, error) {
+	role := d.factory.NewRole()
+	return role, d.body(r, role)
+}
+
+func (d *decoder) domain(r *http.Request) (caskin.Domain, error) {
+	domain := d.factory.NewDomain()
+	return domain, d.body(r, domain)
+}
+
+func (d *decoder) usersForRole(r *http.Request) (*caskin.UsersForRole, error) {
+	raw := &usersForRoleJSON{}
+	if err := d.body(r, raw); err != nil {
+		return nil, err
+	}
+
+	ur := &caskin.UsersForRole{Role: d.factory.NewRole()}
+	if err := d.entry(raw.Role, ur.Role); err != nil {
+		return nil, err
+	}
+	for _, v := range raw.Users {
+		user := d.factory.NewUser()
+		if err := d.entry(v, user); err != nil {
+			return nil, err
+		}
+		ur.Users = append(ur.Users, user)
+	}
+
+	return ur, nil
+}
+
+func (d *decoder) rolesForUser(r *http.Request) (*caskin.RolesForUser, error) {
+	raw := &rolesForUserJSON{}
+	if err := d.body(r, raw); err != nil {
+		return nil, err
+	}
+
+	ru := &caskin.RolesForUser{User: d.factory.NewUser()}
+	if err := d.entry(raw.User, ru.User); err != nil {
+		return nil, err
+	}
+	for _, v := range raw.Roles {
+		role := d.factory.NewRole()
+		if err := d.entry(v, role); err != nil {
+			return nil, err
+		}
+		ru.Roles = append(ru.Roles, role)
+	}
+
+	return ru, nil
+}
+
+// pathID get the id at the end of request's path after the prefix
+func pathID(r *http.Request, prefix string) (uint64, error) {
+	id, err := strconv.ParseUint(strings.TrimPrefix(r.URL.Path, prefix), 10, 64)
+	if err != nil {
+		return 0, invalidRequest(err)
+	}
+	return id, nil
+}
+
+// deleteOption get the DeleteOption from request's query parameter "option"
+func deleteOption(r *http.Request) []caskin.DeleteOption {
+	if option := r.URL.Query().Get("option"); option != "" {
+		return []caskin.DeleteOption{caskin.DeleteOption(option)}
+	}
+	return nil
+}
+
+func invalidRequest(err error) error {
+	return fmt.Errorf("%w: %v", caskin.ErrInvalidRequest, err)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func methodNotAllowed(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusMethodNotAllowed, &errorJSON{
+		Code:    CodeMethodNotAllowed,
+		Message: fmt.Sprintf("method %v is not allowed", r.Method),
+	})
+}
+
+// writeError write the error as JSON with its code and HTTP status
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, caskin.GetHTTPStatus(err), &errorJSON{Code: caskin.GetErrorCode(err), Message: err.Error()})
+}
@@ -0,0 +1,42 @@
+// Package caskinhttp exposes caskin's executor operations as a JSON REST API over net/http,
+// and protects routes by caskin's objects with the Authorizer middleware.
+package caskinhttp
+
+import (
+	"net/http"
+
+	"github.com/awatercolorpen/caskin"
+)
+
+// Executor the operations of caskin's executor served by the Handler,
+// caskin's executor built by GetExecutor implements it
+type Executor interface {
+	GetAllUsersForRole() ([]*caskin.UsersForRole, error)
+	ModifyUsersForRole(*caskin.UsersForRole) error
+	GetAllRolesForUser() ([]*caskin.RolesForUser, error)
+	ModifyRolesForUser(*caskin.RolesForUser) error
+
+	CreateRole(caskin.Role) error
+	RecoverRole(caskin.Role) error
+	DeleteRole(caskin.Role, ...caskin.DeleteOption) error
+
+	DeleteObject(caskin.Object, ...caskin.DeleteOption) error
+
+	CreateDomain(caskin.Domain) error
+	RecoverDomain(caskin.Domain) error
+	DeleteDomain(caskin.Domain, ...caskin.DeleteOption) error
+	UpdateDomain(caskin.Domain) error
+	GetAllDomain() ([]caskin.Domain, error)
+
+	AddSuperadminUser(caskin.User) error
+	DeleteSuperadminUser(caskin.User) error
+	GetAllSuperadminUser() ([]caskin.User, error)
+}
+
+// ExecutorBuilder build the Executor for the current user, such as
+//
+//	func(p caskin.CurrentUserProvider) caskinhttp.Executor { return m.GetExecutor(p) }
+type ExecutorBuilder func(caskin.CurrentUserProvider) Executor
+
+// Authenticator authenticate the request and provide its current user and domain
+type Authenticator func(*http.Request) (caskin.CurrentUserProvider, error)
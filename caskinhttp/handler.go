@@ -0,0 +1,265 @@
+package caskinhttp
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/awatercolorpen/caskin"
+)
+
+// Handler the http.Handler of caskin's REST API, use http.StripPrefix to mount it under a path
+//
+//	GET    /openapi.json            the OpenAPI document
+//	GET    /users-for-role          GetAllUsersForRole
+//	PUT    /users-for-role          ModifyUsersForRole
+//	GET    /roles-for-user          GetAllRolesForUser
+//	PUT    /roles-for-user          ModifyRolesForUser
+//	POST   /roles                   CreateRole
+//	POST   /roles/recover           RecoverRole
+//	DELETE /roles/{id}?option=      DeleteRole
+//	DELETE /objects/{id}?option=    DeleteObject
+//	GET    /domains                 GetAllDomain
+//	POST   /domains                 CreateDomain
+//	POST   /domains/recover         RecoverDomain
+//	PUT    /domains/{id}            UpdateDomain
+//	DELETE /domains/{id}?option=    DeleteDomain
+//	GET    /superadmins             GetAllSuperadminUser
+//	POST   /superadmins/{id}        AddSuperadminUser
+//	DELETE /superadmins/{id}        DeleteSuperadminUser
+type Handler struct {
+	builder ExecutorBuilder
+	auth    Authenticator
+	decoder *decoder
+	mux     *http.ServeMux
+}
+
+// MaxBodyBytes the limit of request's body, the larger one fails to be decoded as invalid request
+const MaxBodyBytes int64 = 1 << 20
+
+// operation an executor operation of a request, the result is written as JSON if it is not nil
+type operation func(Executor, *http.Request) (interface{}, error)
+
+// NewHandler build the Handler
+// 1. authenticate every request except the OpenAPI document by the Authenticator
+// 2. build the Executor for the request's current user by the ExecutorBuilder
+// 3. decode request's JSON body up to MaxBodyBytes into entries by the EntryFactory
+func NewHandler(builder ExecutorBuilder, factory caskin.EntryFactory, auth Authenticator) *Handler {
+	h := &Handler{
+		builder: builder,
+		auth:    auth,
+		decoder: &decoder{factory: factory},
+		mux:     http.NewServeMux(),
+	}
+
+	h.mux.HandleFunc("/openapi.json", h.openAPI)
+	h.handle("/users-for-role", map[string]operation{
+		http.MethodGet: h.getAllUsersForRole,
+		http.MethodPut: h.modifyUsersForRole,
+	})
+	h.handle("/roles-for-user", map[string]operation{
+		http.MethodGet: h.getAllRolesForUser,
+		http.MethodPut: h.modifyRolesForUser,
+	})
+	h.handle("/roles", map[string]operation{
+		http.MethodPost: h.createRole,
+	})
+	h.handle("/roles/recover", map[string]operation{
+		http.MethodPost: h.recoverRole,
+	})
+	h.handle("/roles/", map[string]operation{
+		http.MethodDelete: h.deleteRole,
+	})
+	h.handle("/objects/", map[string]operation{
+		http.MethodDelete: h.deleteObject,
+	})
+	h.handle("/domains", map[string]operation{
+		http.MethodGet:  h.getAllDomain,
+		http.MethodPost: h.createDomain,
+	})
+	h.handle("/domains/recover", map[string]operation{
+		http.MethodPost: h.recoverDomain,
+	})
+	h.handle("/domains/", map[string]operation{
+		http.MethodPut:    h.updateDomain,
+		http.MethodDelete: h.deleteDomain,
+	})
+	h.handle("/superadmins", map[string]operation{
+		http.MethodGet: h.getAllSuperadminUser,
+	})
+	h.handle("/superadmins/", map[string]operation{
+		http.MethodPost:   h.addSuperadminUser,
+		http.MethodDelete: h.deleteSuperadminUser,
+	})
+
+	return h
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler) handle(pattern string, operations map[string]operation) {
+	h.mux.HandleFunc(pattern, func(w http.ResponseWriter, r *http.Request) {
+		fn, ok := operations[r.Method]
+		if !ok {
+			methodNotAllowed(w, r)
+			return
+		}
+
+		provider, err := h.auth(r)
+		if err != nil {
+			writeError(w, fmt.Errorf("%w: %v", caskin.ErrUnauthorized, err))
+			return
+		}
+
+		r.Body = http.MaxBytesReader(w, r.Body, MaxBodyBytes)
+
+		out, err := fn(h.builder(provider), r)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		if out == nil {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		writeJSON(w, http.StatusOK, out)
+	})
+}
+
+func (h *Handler) openAPI(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		methodNotAllowed(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_, _ = w.Write([]byte(OpenAPI))
+}
+
+func (h *Handler) getAllUsersForRole(e Executor, r *http.Request) (interface{}, error) {
+	return e.GetAllUsersForRole()
+}
+
+func (h *Handler) modifyUsersForRole(e Executor, r *http.Request) (interface{}, error) {
+	ur, err := h.decoder.usersForRole(r)
+	if err != nil {
+		return nil, err
+	}
+	return nil, e.ModifyUsersForRole(ur)
+}
+
+func (h *Handler) getAllRolesForUser(e Executor, r *http.Request) (interface{}, error) {
+	return e.GetAllRolesForUser()
+}
+
+func (h *Handler) modifyRolesForUser(e Executor, r *http.Request) (interface{}, error) {
+	ru, err := h.decoder.rolesForUser(r)
+	if err != nil {
+		return nil, err
+	}
+	return nil, e.ModifyRolesForUser(ru)
+}
+
+func (h *Handler) createRole(e Executor, r *http.Request) (interface{}, error) {
+	role, err := h.decoder.role(r)
+	if err != nil {
+		return nil, err
+	}
+	return role, e.CreateRole(role)
+}
+
+func (h *Handler) recoverRole(e Executor, r *http.Request) (interface{}, error) {
+	role, err := h.decoder.role(r)
+	if err != nil {
+		return nil, err
+	}
+	return role, e.RecoverRole(role)
+}
+
+func (h *Handler) deleteRole(e Executor, r *http.Request) (interface{}, error) {
+	id, err := pathID(r, "/roles/")
+	if err != nil {
+		return nil, err
+	}
+	role := h.decoder.factory.NewRole()
+	role.SetID(id)
+	return nil, e.DeleteRole(role, deleteOption(r)...)
+}
+
+func (h *Handler) deleteObject(e Executor, r *http.Request) (interface{}, error) {
+	id, err := pathID(r, "/objects/")
+	if err != nil {
+		return nil, err
+	}
+	object := h.decoder.factory.NewObject()
+	object.SetID(id)
+	return nil, e.DeleteObject(object, deleteOption(r)...)
+}
+
+func (h *Handler) getAllDomain(e Executor, r *http.Request) (interface{}, error) {
+	return e.GetAllDomain()
+}
+
+func (h *Handler) createDomain(e Executor, r *http.Request) (interface{}, error) {
+	domain, err := h.decoder.domain(r)
+	if err != nil {
+		return nil, err
+	}
+	return domain, e.CreateDomain(domain)
+}
+
+func (h *Handler) recoverDomain(e Executor, r *http.Request) (interface{}, error) {
+	domain, err := h.decoder.domain(r)
+	if err != nil {
+		return nil, err
+	}
+	return domain, e.RecoverDomain(domain)
+}
+
+func (h *Handler) updateDomain(e Executor, r *http.Request) (interface{}, error) {
+	id, err := pathID(r, "/domains/")
+	if err != nil {
+		return nil, err
+	}
+	domain, err := h.decoder.domain(r)
+	if err != nil {
+		return nil, err
+	}
+	domain.SetID(id)
+	return nil, e.UpdateDomain(domain)
+}
+
+func (h *Handler) deleteDomain(e Executor, r *http.Request) (interface{}, error) {
+	id, err := pathID(r, "/domains/")
+	if err != nil {
+		return nil, err
+	}
+	domain := h.decoder.factory.NewDomain()
+	domain.SetID(id)
+	return nil, e.DeleteDomain(domain, deleteOption(r)...)
+}
+
+func (h *Handler) getAllSuperadminUser(e Executor, r *http.Request) (interface{}, error) {
+	return e.GetAllSuperadminUser()
+}
+
+func (h *Handler) addSuperadminUser(e Executor, r *http.Request) (interface{}, error) {
+	id, err := pathID(r, "/superadmins/")
+	if err != nil {
+		return nil, err
+	}
+	user := h.decoder.factory.NewUser()
+	user.SetID(id)
+	return nil, e.AddSuperadminUser(user)
+}
+
+func (h *Handler) deleteSuperadminUser(e Executor, r *http.Request) (interface{}, error) {
+	id, err := pathID(r, "/superadmins/")
+	if err != nil {
+		return nil, err
+	}
+	user := h.decoder.factory.NewUser()
+	user.SetID(id)
+	return nil, e.DeleteSuperadminUser(user)
+}
@@ -0,0 +1,110 @@
+package caskinhttp_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/caskinhttp"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+// roleExecutor the Executor which only creates roles
+type roleExecutor struct {
+	caskinhttp.Executor
+	created []caskin.Role
+}
+
+func (r *roleExecutor) CreateRole(role caskin.Role) error {
+	r.created = append(r.created, role)
+	return nil
+}
+
+type provider struct{}
+
+func (p *provider) Get() (caskin.User, caskin.Domain, error) {
+	return &example.User{ID: 1}, &example.Domain{ID: 1}, nil
+}
+
+func authenticate(r *http.Request) (caskin.CurrentUserProvider, error) {
+	if r.Header.Get("Authorization") == "" {
+		return nil, fmt.Errorf("no token")
+	}
+	return &provider{}, nil
+}
+
+func serve(h http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	r.Header.Set("Authorization", "token")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func errorCode(t *testing.T, w *httptest.ResponseRecorder) caskin.ErrorCode {
+	out := struct {
+		Code caskin.ErrorCode `json:"code"`
+	}{}
+	if err := json.NewDecoder(w.Body).Decode(&out); err != nil {
+		t.Fatal(err)
+	}
+	return out.Code
+}
+
+func newHandler() (*caskinhttp.Handler, *roleExecutor) {
+	e := &roleExecutor{}
+	builder := func(caskin.CurrentUserProvider) caskinhttp.Executor { return e }
+	return caskinhttp.NewHandler(builder, example.NewEntryFactory(), authenticate), e
+}
+
+func TestHandler(t *testing.T) {
+	h, e := newHandler()
+	if w := serve(h, http.MethodPost, "/roles", `{"name":"admin"}`); w.Code != http.StatusOK {
+		t.Fatal(w.Code, w.Body.String())
+	}
+	if len(e.created) != 1 || e.created[0].(*example.Role).Name != "admin" {
+		t.Fatal(e.created)
+	}
+
+	if w := serve(h, http.MethodGet, "/roles", ""); w.Code != http.StatusMethodNotAllowed || errorCode(t, w) != caskinhttp.CodeMethodNotAllowed {
+		t.Fatal(w.Code)
+	}
+	r := httptest.NewRequest(http.MethodPost, "/roles", strings.NewReader(`{}`))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusUnauthorized || errorCode(t, w) != caskin.CodeUnauthorized {
+		t.Fatal(w.Code)
+	}
+}
+
+func TestHandlerMaxBodyBytes(t *testing.T) {
+	h, e := newHandler()
+	body := fmt.Sprintf(`{"name":"%v"}`, strings.Repeat("a", int(caskinhttp.MaxBodyBytes)))
+	w := serve(h, http.MethodPost, "/roles", body)
+	if w.Code != http.StatusBadRequest || errorCode(t, w) != caskin.CodeInvalidRequest {
+		t.Fatal(w.Code)
+	}
+	if len(e.created) != 0 {
+		t.Fatal(e.created)
+	}
+}
+
+func TestHandlerOpenAPI(t *testing.T) {
+	h, _ := newHandler()
+	r := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	if w.Code != http.StatusOK || !json.Valid(w.Body.Bytes()) {
+		t.Fatal(w.Code)
+	}
+
+	for _, method := range []string{http.MethodPost, http.MethodDelete} {
+		if w := serve(h, method, "/openapi.json", ""); w.Code != http.StatusMethodNotAllowed {
+			t.Fatal(method, w.Code)
+		}
+	}
+}
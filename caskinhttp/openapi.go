@@ -0,0 +1,197 @@
+package caskinhttp
+
+// OpenAPI the OpenAPI document of the Handler, served at GET /openapi.json.
+// entries are the JSON of the EntryFactory's User, Role and Domain, so their schema is left open
+const OpenAPI = `{
+  "openapi": "3.0.3",
+  "info": {
+    "title": "caskin",
+    "description": "caskin's executor operations as a JSON REST API",
+    "version": "1.0.0"
+  },
+  "paths": {
+    "/users-for-role": {
+      "get": {
+        "operationId": "GetAllUsersForRole",
+        "responses": {
+          "200": {"description": "users of every role", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/UsersForRole"}}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "put": {
+        "operationId": "ModifyUsersForRole",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/UsersForRole"}}}},
+        "responses": {
+          "204": {"description": "modified"},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/roles-for-user": {
+      "get": {
+        "operationId": "GetAllRolesForUser",
+        "responses": {
+          "200": {"description": "roles of every user", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/RolesForUser"}}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "put": {
+        "operationId": "ModifyRolesForUser",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/RolesForUser"}}}},
+        "responses": {
+          "204": {"description": "modified"},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/roles": {
+      "post": {
+        "operationId": "CreateRole",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Role"}}}},
+        "responses": {
+          "200": {"description": "created role", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Role"}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/roles/recover": {
+      "post": {
+        "operationId": "RecoverRole",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Role"}}}},
+        "responses": {
+          "200": {"description": "recovered role", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Role"}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/roles/{id}": {
+      "delete": {
+        "operationId": "DeleteRole",
+        "parameters": [{"$ref": "#/components/parameters/ID"}, {"$ref": "#/components/parameters/DeleteOption"}],
+        "responses": {
+          "204": {"description": "deleted"},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/objects/{id}": {
+      "delete": {
+        "operationId": "DeleteObject",
+        "parameters": [{"$ref": "#/components/parameters/ID"}, {"$ref": "#/components/parameters/DeleteOption"}],
+        "responses": {
+          "204": {"description": "deleted"},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/domains": {
+      "get": {
+        "operationId": "GetAllDomain",
+        "responses": {
+          "200": {"description": "all domains", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/Domain"}}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "post": {
+        "operationId": "CreateDomain",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Domain"}}}},
+        "responses": {
+          "200": {"description": "created domain", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Domain"}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/domains/recover": {
+      "post": {
+        "operationId": "RecoverDomain",
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Domain"}}}},
+        "responses": {
+          "200": {"description": "recovered domain", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Domain"}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/domains/{id}": {
+      "put": {
+        "operationId": "UpdateDomain",
+        "parameters": [{"$ref": "#/components/parameters/ID"}],
+        "requestBody": {"required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Domain"}}}},
+        "responses": {
+          "204": {"description": "updated"},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "delete": {
+        "operationId": "DeleteDomain",
+        "parameters": [{"$ref": "#/components/parameters/ID"}, {"$ref": "#/components/parameters/DeleteOption"}],
+        "responses": {
+          "204": {"description": "deleted"},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/superadmins": {
+      "get": {
+        "operationId": "GetAllSuperadminUser",
+        "responses": {
+          "200": {"description": "all superadmin users", "content": {"application/json": {"schema": {"type": "array", "items": {"$ref": "#/components/schemas/User"}}}}},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    },
+    "/superadmins/{id}": {
+      "post": {
+        "operationId": "AddSuperadminUser",
+        "parameters": [{"$ref": "#/components/parameters/ID"}],
+        "responses": {
+          "204": {"description": "added"},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      },
+      "delete": {
+        "operationId": "DeleteSuperadminUser",
+        "parameters": [{"$ref": "#/components/parameters/ID"}],
+        "responses": {
+          "204": {"description": "deleted"},
+          "default": {"$ref": "#/components/responses/Error"}
+        }
+      }
+    }
+  },
+  "components": {
+    "parameters": {
+      "ID": {"name": "id", "in": "path", "required": true, "schema": {"type": "integer", "format": "uint64"}},
+      "DeleteOption": {"name": "option", "in": "query", "required": false, "schema": {"type": "string", "enum": ["restrict", "cascade", "detach"]}}
+    },
+    "responses": {
+      "Error": {"description": "error", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Error"}}}}
+    },
+    "schemas": {
+      "User": {"type": "object", "additionalProperties": true},
+      "Role": {"type": "object", "additionalProperties": true},
+      "Domain": {"type": "object", "additionalProperties": true},
+      "UsersForRole": {
+        "type": "object",
+        "properties": {
+          "role": {"$ref": "#/components/schemas/Role"},
+          "users": {"type": "array", "items": {"$ref": "#/components/schemas/User"}}
+        }
+      },
+      "RolesForUser": {
+        "type": "object",
+        "properties": {
+          "user": {"$ref": "#/components/schemas/User"},
+          "roles": {"type": "array", "items": {"$ref": "#/components/schemas/Role"}}
+        }
+      },
+      "Error": {
+        "type": "object",
+        "properties": {
+          "code": {"type": "string"},
+          "message": {"type": "string"}
+        }
+      }
+    }
+  }
+}
+`
@@ -0,0 +1,122 @@
+package caskin_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+type cloneExecutor interface {
+	CloneDomain(caskin.Domain, caskin.Domain, bool) error
+}
+
+// rigidRole the role can't set its object code, the field shadows Role's SetObject
+type rigidRole struct {
+	example.Role
+	SetObject struct{} `gorm:"-" json:"-"`
+}
+
+func (r *rigidRole) TableName() string {
+	return "roles"
+}
+
+type rigidFactory struct {
+	caskin.EntryFactory
+}
+
+func (f *rigidFactory) NewRole() caskin.Role {
+	return &rigidRole{}
+}
+
+func TestCloneDomain(t *testing.T) {
+	s := newStage(t, nil)
+	s.assign(t, 2, 2)
+	target := &example.Domain{Name: "domain_2"}
+	if err := s.executor(s.as(1)).(cloneExecutor).CloneDomain(&example.Domain{ID: s.domain.ID}, target, true); err != nil {
+		t.Fatal(err)
+	}
+
+	roles, err := s.mdb.GetRoleInDomain(target)
+	if err != nil || len(roles) != 2 {
+		t.Fatal(roles, err)
+	}
+	objects, err := s.mdb.GetObjectInDomain(target)
+	if err != nil || len(objects) != 2 {
+		t.Fatal(objects, err)
+	}
+	if len(s.e.GetRolesForUserInDomain("user_2", target.Encode())) != 1 {
+		t.Fatal("user's g is not cloned")
+	}
+	if len(s.e.GetFilteredPolicy(1, target.Encode())) != 2 {
+		t.Fatal("p is not cloned")
+	}
+
+	if err := s.executor(s.as(1)).(cloneExecutor).CloneDomain(&example.Domain{ID: s.domain.ID}, target, false); !errors.Is(err, caskin.ErrAlreadyExists) {
+		t.Fatal(err)
+	}
+}
+
+func TestCloneDomainPermission(t *testing.T) {
+	s := newStage(t, nil)
+	s.assign(t, 2, 2)
+	// user 2 as member reads root, but neither data nor member whose object is data
+	for _, v := range []interface{}{
+		&example.Object{ID: 1, Object: "object_1"},
+		&example.Role{ID: 1, Object: "object_1"},
+		&example.Role{ID: 2, Object: "object_2"},
+	} {
+		if err := s.db.Updates(v).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	target := &example.Domain{Name: "domain_2"}
+	if err := s.executor(s.as(2)).(cloneExecutor).CloneDomain(&example.Domain{ID: s.domain.ID}, target, true); err != nil {
+		t.Fatal(err)
+	}
+	roles, _ := s.mdb.GetRoleInDomain(target)
+	objects, _ := s.mdb.GetObjectInDomain(target)
+	if len(roles) != 1 || roles[0].(*example.Role).Name != "admin" || len(objects) != 1 || objects[0].(*example.Object).Name != "root" {
+		t.Fatal(roles, objects)
+	}
+	if roles[0].GetObject() != objects[0].Encode() {
+		t.Fatal("role's object code is not remapped")
+	}
+
+	other := &example.Domain{Name: "domain_3"}
+	provider := &provider{user: &example.User{ID: 2}, domain: other}
+	if err := s.executor(provider).(cloneExecutor).CloneDomain(&example.Domain{ID: s.domain.ID}, other, false); !errors.Is(err, caskin.ErrCrossDomain) {
+		t.Fatal(err)
+	}
+}
+
+func TestCloneDomainRollback(t *testing.T) {
+	s := newStage(t, nil)
+	if err := s.db.Updates(&example.Role{ID: 1, Object: "object_1"}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := caskin.New(s.mdb, s.e, &rigidFactory{example.NewEntryFactory()}, &caskin.Option{
+		SuperAdminOption: &caskin.SuperAdminOption{Enable: true},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	target := &example.Domain{Name: "domain_2"}
+	if err := m.GetExecutor(s.as(1)).CloneDomain(&example.Domain{ID: s.domain.ID}, target, false); !errors.Is(err, caskin.ErrInvalidEntryType) {
+		t.Fatal(err)
+	}
+
+	if err := s.mdb.TakeDomain(&example.Domain{ID: target.ID}); err == nil {
+		t.Fatal("target domain is not rolled back")
+	}
+	objects, err := s.mdb.GetObjectInDomain(target)
+	if err != nil || len(objects) != 0 {
+		t.Fatal(objects, err)
+	}
+	if rules := s.e.GetFilteredNamedGroupingPolicy("g2", 2, target.Encode()); len(rules) != 0 {
+		t.Fatal(rules)
+	}
+}
@@ -0,0 +1,412 @@
+package main
+
+import (
+	"bytes"
+	"go/format"
+	"strings"
+	"text/template"
+)
+
+var funcs = template.FuncMap{
+	"kinds": func() []string {
+		return []string{string(kindUser), string(kindRole), string(kindObject), string(kindDomain)}
+	},
+	"title": title,
+}
+
+// title upper the first letter of the kind, such as "user" to "User"
+func title(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+var entryTemplate = template.Must(template.New("entry").Funcs(funcs).Parse(`// Code generated by caskin-gen. DO NOT EDIT.
+
+package {{.Name}}
+
+import "github.com/awatercolorpen/caskin"
+
+var (
+{{- range .Entries}}
+	{{.Codec}} caskin.EntryCodec = caskin.PrefixCodec({{printf "%q" .Prefix}})
+{{- end}}
+)
+{{range .Entries}}{{$r := .Receiver}}
+func ({{$r}} *{{.Type}}) GetID() uint64 {
+	return {{$r}}.{{.Field "id"}}
+}
+
+func ({{$r}} *{{.Type}}) SetID(id uint64) {
+	{{$r}}.{{.Field "id"}} = id
+}
+
+func ({{$r}} *{{.Type}}) Encode() string {
+	return {{.Codec}}.Encode({{$r}}.{{.Field "id"}})
+}
+
+func ({{$r}} *{{.Type}}) Decode(code string) error {
+	id, err := {{.Codec}}.Decode(code)
+	if err != nil {
+		return err
+	}
+	{{$r}}.{{.Field "id"}} = id
+	return nil
+}
+
+func ({{$r}} *{{.Type}}) IsObject() bool {
+	return {{if .Field "object"}}true{{else}}false{{end}}
+}
+
+func ({{$r}} *{{.Type}}) GetObject() string {
+	return {{if .Field "object"}}{{$r}}.{{.Field "object"}}{{else}}""{{end}}
+}
+{{- if .Field "object"}}
+
+func ({{$r}} *{{.Type}}) SetObject(object string) {
+	{{$r}}.{{.Field "object"}} = object
+}
+{{- end}}
+{{- if .Field "parent"}}
+
+func ({{$r}} *{{.Type}}) GetParentID() uint64 {
+	return {{$r}}.{{.Field "parent"}}
+}
+
+func ({{$r}} *{{.Type}}) SetParentID(pid uint64) {
+	{{$r}}.{{.Field "parent"}} = pid
+}
+{{- end}}
+{{- if .Field "domain"}}
+
+func ({{$r}} *{{.Type}}) GetDomainID() uint64 {
+	return {{$r}}.{{.Field "domain"}}
+}
+
+func ({{$r}} *{{.Type}}) SetDomainID(did uint64) {
+	{{$r}}.{{.Field "domain"}} = did
+}
+{{- end}}
+{{- if .Field "type"}}
+
+func ({{$r}} *{{.Type}}) GetObjectType() caskin.ObjectType {
+	return caskin.ObjectType({{$r}}.{{.Field "type"}})
+}
+{{- end}}
+{{end}}
+{{- if .HasFactory}}
+type entryFactory struct {
+}
+
+func (e *entryFactory) NewUser() caskin.User {
+	return &{{(.Entry "user").Type}}{}
+}
+
+func (e *entryFactory) NewRole() caskin.Role {
+	return &{{(.Entry "role").Type}}{}
+}
+
+func (e *entryFactory) NewObject() caskin.Object {
+	return &{{(.Entry "object").Type}}{}
+}
+
+func (e *entryFactory) NewDomain() caskin.Domain {
+	return &{{(.Entry "domain").Type}}{}
+}
+
+func NewEntryFactory() caskin.EntryFactory {
+	return &entryFactory{}
+}
+{{range $k := kinds}}{{with $.Entry $k}}
+func to{{title $k}}s(in []*{{.Type}}) []caskin.{{title $k}} {
+	var out []caskin.{{title $k}}
+	for _, v := range in {
+		out = append(out, v)
+	}
+	return out
+}
+{{end}}{{end}}
+{{- end}}`))
+
+var mdbTemplate = template.Must(template.New("mdb").Funcs(funcs).Parse(`// Code generated by caskin-gen. DO NOT EDIT.
+
+package {{.Name}}
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/awatercolorpen/caskin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+{{$user := .Entry "user"}}{{$role := .Entry "role"}}{{$object := .Entry "object"}}{{$domain := .Entry "domain"}}
+type gormMDB struct {
+	db *gorm.DB
+}
+
+func (g *gormMDB) TakeUser(user caskin.User) error {
+	return g.db.Where(user).Take(user).Error
+}
+
+func (g *gormMDB) GetUserByID(id []uint64) ([]caskin.User, error) {
+	var user []*{{$user.Type}}
+	db, err := whereID(g.db, &{{$user.Type}}{}, "{{$user.Field "id"}}", id)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Find(&user).Error; err != nil {
+		return nil, err
+	}
+	return toUsers(user), nil
+}
+
+func (g *gormMDB) CreateRole(role caskin.Role) error {
+	return g.db.Create(role).Error
+}
+
+func (g *gormMDB) RecoverRole(role caskin.Role) error {
+	return recoverEntry(g.db, role)
+}
+
+func (g *gormMDB) UpdateRole(role caskin.Role) error {
+	return g.db.Updates(role).Error
+}
+
+func (g *gormMDB) TakeRole(role caskin.Role) error {
+	return g.db.Where(role).Take(role).Error
+}
+
+func (g *gormMDB) GetRoleInDomain(domain caskin.Domain) ([]caskin.Role, error) {
+	var role []*{{$role.Type}}
+	if err := g.db.Where(&{{$role.Type}}{ {{- $role.Field "domain"}}: domain.GetID()}).Find(&role).Error; err != nil {
+		return nil, err
+	}
+	return toRoles(role), nil
+}
+
+func (g *gormMDB) GetRoleByID(id []uint64) ([]caskin.Role, error) {
+	var role []*{{$role.Type}}
+	db, err := whereID(g.db, &{{$role.Type}}{}, "{{$role.Field "id"}}", id)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Find(&role).Error; err != nil {
+		return nil, err
+	}
+	return toRoles(role), nil
+}
+
+func (g *gormMDB) UpsertRole(role caskin.Role) error {
+	return upsertEntry(g.db, role)
+}
+
+func (g *gormMDB) DeleteRoleByID(id uint64) error {
+	return g.db.Delete(&{{$role.Type}}{}, id).Error
+}
+
+func (g *gormMDB) GetDeletedRoleInDomain(domain caskin.Domain, before time.Time) ([]caskin.Role, error) {
+	var role []*{{$role.Type}}
+	db, err := deletedBefore(g.db, &{{$role.Type}}{}, before)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Where(&{{$role.Type}}{ {{- $role.Field "domain"}}: domain.GetID()}).Find(&role).Error; err != nil {
+		return nil, err
+	}
+	return toRoles(role), nil
+}
+
+func (g *gormMDB) PurgeRoleByID(id uint64) error {
+	return g.db.Unscoped().Delete(&{{$role.Type}}{}, id).Error
+}
+
+func (g *gormMDB) CreateObject(object caskin.Object) error {
+	return g.db.Create(object).Error
+}
+
+func (g *gormMDB) RecoverObject(object caskin.Object) error {
+	return recoverEntry(g.db, object)
+}
+
+func (g *gormMDB) UpdateObject(object caskin.Object) error {
+	return g.db.Updates(object).Error
+}
+
+func (g *gormMDB) TakeObject(object caskin.Object) error {
+	return g.db.Where(object).Take(object).Error
+}
+
+func (g *gormMDB) GetObjectInDomain(domain caskin.Domain, objectType ...caskin.ObjectType) ([]caskin.Object, error) {
+	o := &{{$object.Type}}{ {{- $object.Field "domain"}}: domain.GetID()}
+	if len(objectType) > 0 {
+		o.{{$object.Field "type"}} = {{$object.FieldType "type"}}(objectType[0])
+	}
+
+	var object []*{{$object.Type}}
+	if err := g.db.Where(o).Find(&object).Error; err != nil {
+		return nil, err
+	}
+	return toObjects(object), nil
+}
+
+func (g *gormMDB) GetObjectByID(id []uint64) ([]caskin.Object, error) {
+	var object []*{{$object.Type}}
+	db, err := whereID(g.db, &{{$object.Type}}{}, "{{$object.Field "id"}}", id)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Find(&object).Error; err != nil {
+		return nil, err
+	}
+	return toObjects(object), nil
+}
+
+func (g *gormMDB) UpsertObject(object caskin.Object) error {
+	return upsertEntry(g.db, object)
+}
+
+func (g *gormMDB) DeleteObjectByID(id uint64) error {
+	return g.db.Delete(&{{$object.Type}}{}, id).Error
+}
+
+func (g *gormMDB) GetDeletedObjectInDomain(domain caskin.Domain, before time.Time) ([]caskin.Object, error) {
+	var object []*{{$object.Type}}
+	db, err := deletedBefore(g.db, &{{$object.Type}}{}, before)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Where(&{{$object.Type}}{ {{- $object.Field "domain"}}: domain.GetID()}).Find(&object).Error; err != nil {
+		return nil, err
+	}
+	return toObjects(object), nil
+}
+
+func (g *gormMDB) PurgeObjectByID(id uint64) error {
+	return g.db.Unscoped().Delete(&{{$object.Type}}{}, id).Error
+}
+
+func (g *gormMDB) CreateDomain(domain caskin.Domain) error {
+	return g.db.Create(domain).Error
+}
+
+func (g *gormMDB) RecoverDomain(domain caskin.Domain) error {
+	return recoverEntry(g.db, domain)
+}
+
+func (g *gormMDB) UpdateDomain(domain caskin.Domain) error {
+	return g.db.Updates(domain).Error
+}
+
+func (g *gormMDB) TakeDomain(domain caskin.Domain) error {
+	return g.db.Where(domain).Take(domain).Error
+}
+
+func (g *gormMDB) GetAllDomain() ([]caskin.Domain, error) {
+	var domain []*{{$domain.Type}}
+	if err := g.db.Find(&domain).Error; err != nil {
+		return nil, err
+	}
+	return toDomains(domain), nil
+}
+
+func (g *gormMDB) DeleteDomainByID(id uint64) error {
+	return g.db.Delete(&{{$domain.Type}}{}, id).Error
+}
+
+func (g *gormMDB) GetAllDeletedDomain(before time.Time) ([]caskin.Domain, error) {
+	var domain []*{{$domain.Type}}
+	db, err := deletedBefore(g.db, &{{$domain.Type}}{}, before)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Find(&domain).Error; err != nil {
+		return nil, err
+	}
+	return toDomains(domain), nil
+}
+
+func (g *gormMDB) PurgeDomainByID(id uint64) error {
+	return g.db.Unscoped().Delete(&{{$domain.Type}}{}, id).Error
+}
+
+func NewGormMDB(db *gorm.DB) caskin.MetaDB {
+	return &gormMDB{
+		db: db,
+	}
+}
+
+func upsertEntry(db *gorm.DB, entry interface{ GetID() uint64 }) error {
+	if entry.GetID() == 0 {
+		return insertOrRecover(db, entry)
+	}
+	return db.Updates(entry).Error
+}
+
+func insertOrRecover(db *gorm.DB, item interface{}) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where(item).Take(item).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return tx.Create(item).Error
+			}
+			return err
+		}
+		return tx.Unscoped().Model(item).Update("DeletedAt", nil).Error
+	})
+}
+
+// whereID the records whose id field is in the id list, the column is got by the model's schema
+func whereID(db *gorm.DB, model interface{}, field string, id []uint64) (*gorm.DB, error) {
+	column, err := lookUpColumn(db, model, field)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]interface{}, len(id))
+	for i, v := range id {
+		values[i] = v
+	}
+	return db.Where(clause.IN{Column: column, Values: values}), nil
+}
+
+// deletedBefore the records soft deleted before the time
+func deletedBefore(db *gorm.DB, model interface{}, before time.Time) (*gorm.DB, error) {
+	column, err := lookUpColumn(db, model, "DeletedAt")
+	if err != nil {
+		return nil, err
+	}
+	return db.Unscoped().Where(clause.Lt{Column: column, Value: before}), nil
+}
+
+// lookUpColumn the column of the model's field, it respects the field's gorm tag and the db's naming strategy
+func lookUpColumn(db *gorm.DB, model interface{}, field string) (string, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return "", err
+	}
+	f := stmt.Schema.LookUpField(field)
+	if f == nil || f.DBName == "" {
+		return "", fmt.Errorf("%v has no column of field %v", stmt.Schema.Name, field)
+	}
+	return f.DBName, nil
+}
+
+func recoverEntry(db *gorm.DB, item interface{}) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where(item).Take(item).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(item).Update("DeletedAt", nil).Error
+	})
+}
+`))
+
+func generate(tpl *template.Template, pkg *pkgInfo) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := tpl.Execute(buf, pkg); err != nil {
+		return nil, err
+	}
+
+	return format.Source(buf.Bytes())
+}
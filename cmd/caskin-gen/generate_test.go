@@ -0,0 +1,213 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const modelSource = `
+//caskin:entry user
+type User struct {
+	ID uint64 ` + "`gorm:\"column:uid\" caskin:\"id\"`" + `
+}
+
+//caskin:entry role
+type Role struct {
+	ID       uint64 ` + "`caskin:\"id\"`" + `
+	Object   string ` + "`caskin:\"object\"`" + `
+	DomainID uint64 ` + "`caskin:\"domain\"`" + `
+	ParentID uint64 ` + "`caskin:\"parent\"`" + `
+}
+
+//caskin:entry object
+type Object struct {
+	ID       uint64 ` + "`caskin:\"id\"`" + `
+	Type     string ` + "`caskin:\"type\"`" + `
+	DomainID uint64 ` + "`caskin:\"domain\"`" + `
+	ParentID uint64 ` + "`caskin:\"parent\"`" + `
+}
+
+//caskin:entry domain
+type Domain struct {
+	ID uint64 ` + "`caskin:\"id\"`" + `
+}
+`
+
+func TestGenerate(t *testing.T) {
+	entries, err := parseSource(t, modelSource)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pkg := &pkgInfo{Name: "model", Entries: entries}
+	if !pkg.HasFactory() {
+		t.Fatal("expect entry factory")
+	}
+
+	code, err := generate(entryTemplate, pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		`caskin.PrefixCodec("user_")`,
+		`func (r *Role) GetObject() string {`,
+		`func (r *Role) SetObject(object string) {`,
+		`func (o *Object) GetObjectType() caskin.ObjectType {`,
+		`func toUsers(in []*User) []caskin.User {`,
+		`func NewEntryFactory() caskin.EntryFactory {`,
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Fatalf("entry code has no %q", want)
+		}
+	}
+
+	code, err = generate(mdbTemplate, pkg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		// the column is looked up by the schema at runtime instead of guessed from the field name
+		`db, err := whereID(g.db, &User{}, "ID", id)`,
+		`db, err := deletedBefore(g.db, &Role{}, before)`,
+		`return "", fmt.Errorf("%v has no column of field %v", stmt.Schema.Name, field)`,
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Fatalf("mdb code has no %q", want)
+		}
+	}
+	if strings.Contains(string(code), " IN ?") {
+		t.Fatal("mdb code guesses the id column")
+	}
+}
+
+// buildModel the gorm models of a package to generate, the user's id column is not guessable from its field name
+const buildModel = `package model
+
+import "gorm.io/gorm"
+
+//caskin:entry user
+type User struct {
+	ID        uint64 ` + "`gorm:\"primaryKey;column:uid\" caskin:\"id\"`" + `
+	DeletedAt gorm.DeletedAt
+}
+
+//caskin:entry role
+type Role struct {
+	ID        uint64 ` + "`caskin:\"id\"`" + `
+	Name      string
+	Object    string ` + "`caskin:\"object\"`" + `
+	DomainID  uint64 ` + "`gorm:\"column:tenant_id\" caskin:\"domain\"`" + `
+	ParentID  uint64 ` + "`gorm:\"-\" caskin:\"parent\"`" + `
+	DeletedAt gorm.DeletedAt
+}
+
+//caskin:entry object
+type Object struct {
+	ID        uint64 ` + "`caskin:\"id\"`" + `
+	Name      string
+	Object    string ` + "`caskin:\"object\"`" + `
+	Type      string ` + "`caskin:\"type\"`" + `
+	DomainID  uint64 ` + "`caskin:\"domain\"`" + `
+	ParentID  uint64 ` + "`gorm:\"-\" caskin:\"parent\"`" + `
+	DeletedAt gorm.DeletedAt
+}
+
+//caskin:entry domain
+type Domain struct {
+	ID        uint64 ` + "`caskin:\"id\"`" + `
+	Name      string
+	DeletedAt gorm.DeletedAt
+}
+`
+
+// buildModelTest the test of the generated package, it checks the generated code by caskin's interfaces and validator,
+// and runs the generated MetaDB on sqlite
+const buildModelTest = `package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/awatercolorpen/caskin"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+var _ caskin.ObjectSetter = &Role{}
+
+func TestGenerated(t *testing.T) {
+	if err := caskin.ValidateEntryFactory(NewEntryFactory(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&User{}, &Role{}, &Object{}, &Domain{}); err != nil {
+		t.Fatal(err)
+	}
+	mdb := NewGormMDB(db)
+	trash, ok := mdb.(caskin.TrashMetaDB)
+	if !ok {
+		t.Fatal("generated MetaDB is not a TrashMetaDB")
+	}
+
+	if err := db.Create(&User{ID: 7}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if users, err := mdb.GetUserByID([]uint64{7}); err != nil || len(users) != 1 {
+		t.Fatal(users, err)
+	}
+	domain := &Domain{Name: "one"}
+	if err := mdb.CreateDomain(domain); err != nil {
+		t.Fatal(err)
+	}
+	role := &Role{Name: "admin", DomainID: domain.ID}
+	if err := mdb.CreateRole(role); err != nil {
+		t.Fatal(err)
+	}
+	if err := mdb.DeleteRoleByID(role.ID); err != nil {
+		t.Fatal(err)
+	}
+	if roles, err := trash.GetDeletedRoleInDomain(domain, time.Now().Add(time.Hour)); err != nil || len(roles) != 1 {
+		t.Fatal(roles, err)
+	}
+}
+`
+
+func TestGenerateBuild(t *testing.T) {
+	if testing.Short() {
+		t.Skip("it builds and tests the generated package")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip(err)
+	}
+
+	// a directory starting with "_" in this module is ignored by ./... but could be built by its path
+	dir, err := ioutil.TempDir(".", "_gen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "model.go"), []byte(buildModel), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := run(dir, "caskin_gen.go", true, "caskin_mdb_gen.go"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, "model_test.go"), []byte(buildModelTest), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"vet", "./" + dir}, {"test", "-count=1", "./" + dir}} {
+		out, err := exec.Command(goTool, args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("go %v: %v\n%s", args[0], err, out)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+// caskin-gen generate caskin's entry methods for model structs.
+//
+// Mark a struct with a directive comment, and its fields with caskin tags:
+//
+//	//caskin:entry role prefix=role_
+//	type Role struct {
+//		ID       uint64 `caskin:"id"`
+//		Object   string `caskin:"object"`
+//		DomainID uint64 `caskin:"domain"`
+//		ParentID uint64 `caskin:"parent"`
+//	}
+//
+// The kind is one of user, role, object, domain, and prefix defaults to "<kind>_".
+// Supported tags are id, parent, domain, object, and type for object's type.
+// The field tagged object gets SetObject too, then the entry is a caskin.ObjectSetter.
+// Then run it in the package directory, for example by go generate:
+//
+//	//go:generate go run github.com/awatercolorpen/caskin/cmd/caskin-gen -mdb
+//
+// If there is exactly one entry of every kind, it generates an EntryFactory by NewEntryFactory too.
+// With -mdb, it generates a gorm MetaDB by NewGormMDB, which needs the entries to be gorm models
+// with a gorm.DeletedAt field named DeletedAt.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+)
+
+var (
+	dir       = flag.String("dir", ".", "directory of the package to generate")
+	output    = flag.String("output", "caskin_gen.go", "file name of the generated entry code")
+	mdb       = flag.Bool("mdb", false, "generate gorm MetaDB too")
+	mdbOutput = flag.String("mdb-output", "caskin_mdb_gen.go", "file name of the generated gorm MetaDB code")
+)
+
+func main() {
+	flag.Parse()
+	if err := run(*dir, *output, *mdb, *mdbOutput); err != nil {
+		fmt.Fprintln(os.Stderr, "caskin-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(dir, output string, mdb bool, mdbOutput string) error {
+	pkg, err := parsePackage(dir, output, mdbOutput)
+	if err != nil {
+		return err
+	}
+
+	code, err := generate(entryTemplate, pkg)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, output), code, 0644); err != nil {
+		return err
+	}
+
+	if !mdb {
+		return nil
+	}
+
+	if !pkg.HasFactory() {
+		return fmt.Errorf("gorm MetaDB requires exactly one entry of every kind")
+	}
+
+	code, err = generate(mdbTemplate, pkg)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dir, mdbOutput), code, 0644)
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+const (
+	directive = "caskin:entry"
+	tagKey    = "caskin"
+)
+
+type kind string
+
+const (
+	kindUser   kind = "user"
+	kindRole   kind = "role"
+	kindObject kind = "object"
+	kindDomain kind = "domain"
+)
+
+// requiredTag the tags every kind of entry must have
+var requiredTag = map[kind][]string{
+	kindUser:   {"id"},
+	kindRole:   {"id", "parent", "domain"},
+	kindObject: {"id", "parent", "domain", "type"},
+	kindDomain: {"id"},
+}
+
+// optionalTag the tags every kind of entry may have besides the required ones
+var optionalTag = map[kind][]string{
+	kindUser:   {"object"},
+	kindRole:   {"object"},
+	kindObject: {"object"},
+	kindDomain: {"object"},
+}
+
+type pkgInfo struct {
+	Name    string
+	Entries []*entryInfo
+}
+
+// Entry get the only entry of the kind, it is nil if there is none or more than one
+func (p *pkgInfo) Entry(k string) *entryInfo {
+	var found *entryInfo
+	for _, v := range p.Entries {
+		if string(v.Kind) != k {
+			continue
+		}
+		if found != nil {
+			return nil
+		}
+		found = v
+	}
+	return found
+}
+
+// HasFactory if there is exactly one entry of every kind, then EntryFactory could be generated
+func (p *pkgInfo) HasFactory() bool {
+	for k := range requiredTag {
+		if p.Entry(string(k)) == nil {
+			return false
+		}
+	}
+	return true
+}
+
+type entryInfo struct {
+	Kind   kind
+	Type   string
+	Prefix string
+	// tag to field name
+	Fields map[string]string
+	// tag to field type
+	Types map[string]string
+}
+
+func (e *entryInfo) Receiver() string {
+	return strings.ToLower(e.Type[:1])
+}
+
+func (e *entryInfo) Codec() string {
+	return strings.ToLower(e.Type[:1]) + e.Type[1:] + "Codec"
+}
+
+func (e *entryInfo) Field(tag string) string {
+	return e.Fields[tag]
+}
+
+func (e *entryInfo) FieldType(tag string) string {
+	return e.Types[tag]
+}
+
+func parsePackage(dir string, skip ...string) (*pkgInfo, error) {
+	fset := token.NewFileSet()
+	filter := func(info os.FileInfo) bool {
+		name := info.Name()
+		for _, v := range skip {
+			if name == v {
+				return false
+			}
+		}
+		return !strings.HasSuffix(name, "_test.go")
+	}
+	pkgs, err := parser.ParseDir(fset, dir, filter, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+	if len(pkgs) != 1 {
+		return nil, fmt.Errorf("expect one package in %v, but got %v", dir, len(pkgs))
+	}
+
+	info := &pkgInfo{}
+	for name, pkg := range pkgs {
+		info.Name = name
+		for _, file := range pkg.Files {
+			entries, err := parseFile(file)
+			if err != nil {
+				return nil, err
+			}
+			info.Entries = append(info.Entries, entries...)
+		}
+	}
+
+	if len(info.Entries) == 0 {
+		return nil, fmt.Errorf("no struct is marked with //%v in %v", directive, dir)
+	}
+
+	sort.Slice(info.Entries, func(i, j int) bool {
+		return info.Entries[i].Type < info.Entries[j].Type
+	})
+	return info, nil
+}
+
+func parseFile(file *ast.File) ([]*entryInfo, error) {
+	var entries []*entryInfo
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			doc := ts.Doc
+			if doc == nil && len(gen.Specs) == 1 {
+				doc = gen.Doc
+			}
+
+			k, prefix, ok, err := parseDirective(doc)
+			if err != nil {
+				return nil, fmt.Errorf("%v: %v", ts.Name.Name, err)
+			}
+			if !ok {
+				continue
+			}
+
+			st, ok := ts.Type.(*ast.StructType)
+			if !ok {
+				return nil, fmt.Errorf("%v is marked as %v but is not a struct", ts.Name.Name, k)
+			}
+
+			e, err := parseStruct(ts.Name.Name, k, prefix, st)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, e)
+		}
+	}
+
+	return entries, nil
+}
+
+// parseDirective parse comment like "//caskin:entry role prefix=role_"
+func parseDirective(doc *ast.CommentGroup) (kind, string, bool, error) {
+	if doc == nil {
+		return "", "", false, nil
+	}
+
+	for _, c := range doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(c.Text, "//"))
+		if !strings.HasPrefix(text, directive) {
+			continue
+		}
+
+		args := strings.Fields(strings.TrimPrefix(text, directive))
+		if len(args) == 0 {
+			return "", "", false, fmt.Errorf("//%v requires the entry kind", directive)
+		}
+
+		k := kind(args[0])
+		prefix := string(k) + "_"
+		for _, v := range args[1:] {
+			if !strings.HasPrefix(v, "prefix=") {
+				return "", "", false, fmt.Errorf("//%v has unknown argument %q", directive, v)
+			}
+			prefix = strings.TrimPrefix(v, "prefix=")
+		}
+		return k, prefix, true, nil
+	}
+
+	return "", "", false, nil
+}
+
+func parseStruct(name string, k kind, prefix string, st *ast.StructType) (*entryInfo, error) {
+	required, ok := requiredTag[k]
+	if !ok {
+		return nil, fmt.Errorf("%v has unknown entry kind %q", name, k)
+	}
+
+	e := &entryInfo{
+		Kind:   k,
+		Type:   name,
+		Prefix: prefix,
+		Fields: map[string]string{},
+		Types:  map[string]string{},
+	}
+
+	for _, field := range st.Fields.List {
+		if field.Tag == nil || len(field.Names) == 0 {
+			continue
+		}
+
+		tag := reflect.StructTag(strings.Trim(field.Tag.Value, "`")).Get(tagKey)
+		if tag == "" {
+			continue
+		}
+
+		if !hasTag(requiredTag[k], tag) && !hasTag(optionalTag[k], tag) {
+			return nil, fmt.Errorf("%v as %v has unknown tag `%v:%q`", name, k, tagKey, tag)
+		}
+		if _, ok := e.Fields[tag]; ok {
+			return nil, fmt.Errorf("%v has duplicated tag %q", name, tag)
+		}
+		e.Fields[tag] = field.Names[0].Name
+		e.Types[tag] = types.ExprString(field.Type)
+	}
+
+	for _, v := range required {
+		if _, ok := e.Fields[v]; !ok {
+			return nil, fmt.Errorf("%v as %v requires a field with tag `%v:%q`", name, k, tagKey, v)
+		}
+	}
+
+	return e, nil
+}
+
+func hasTag(tags []string, tag string) bool {
+	for _, v := range tags {
+		if v == tag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"go/parser"
+	"go/token"
+	"strings"
+	"testing"
+)
+
+func parseSource(t *testing.T, src string) ([]*entryInfo, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), "model.go", "package model\n"+src, parser.ParseComments)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return parseFile(file)
+}
+
+func TestParseFile(t *testing.T) {
+	entries, err := parseSource(t, `
+//caskin:entry role prefix=r:
+type Role struct {
+	ID       uint64 `+"`caskin:\"id\"`"+`
+	Object   string `+"`caskin:\"object\"`"+`
+	DomainID uint64 `+"`caskin:\"domain\"`"+`
+	ParentID uint64 `+"`caskin:\"parent\"`"+`
+	Name     string
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Prefix != "r:" || entries[0].Field("parent") != "ParentID" || entries[0].Field("object") != "Object" {
+		t.Fatal(entries)
+	}
+}
+
+func TestParseFileError(t *testing.T) {
+	for _, c := range []struct {
+		src  string
+		want string
+	}{
+		{"//caskin:entry role\ntype Role struct {\n\tID uint64 `caskin:\"id\"`\n\tDomainID uint64 `caskin:\"domain\"`\n\tParentID uint64 `caskin:\"parnet\"`\n}", `unknown tag`},
+		{"//caskin:entry user\ntype User struct {\n\tID uint64 `caskin:\"id\"`\n\tParentID uint64 `caskin:\"parent\"`\n}", `unknown tag`},
+		{"//caskin:entry user prefx=u:\ntype User struct {\n\tID uint64 `caskin:\"id\"`\n}", `unknown argument`},
+		{"//caskin:entry role\ntype Role struct {\n\tID uint64 `caskin:\"id\"`\n}", `requires a field`},
+		{"//caskin:entry tenant\ntype Tenant struct {\n\tID uint64 `caskin:\"id\"`\n}", `unknown entry kind`},
+	} {
+		if _, err := parseSource(t, c.src); err == nil || !strings.Contains(err.Error(), c.want) {
+			t.Fatalf("%q: %v", c.src, err)
+		}
+	}
+}
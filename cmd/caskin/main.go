@@ -0,0 +1,26 @@
+// caskin the command-line admin tool of caskin for the example's models in sqlite.
+//
+// Applications with their own models build their binary by caskincli.Main the same way, run it such as
+//
+//	caskin -dsn caskin.db -policy policy.csv -domain domain_1 roles
+//	caskin -dsn caskin.db -policy policy.csv -domain domain_1 -as user_1 assign user_2 role_3
+//	caskin -dsn caskin.db -policy policy.csv -as user_1 check -repair
+package main
+
+import (
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/caskincli"
+	"github.com/awatercolorpen/caskin/example"
+	"gorm.io/driver/sqlite"
+)
+
+func main() {
+	caskincli.Main(&caskincli.Config{
+		Factory:   example.NewEntryFactory(),
+		Dialector: sqlite.Open,
+		MetaDB:    example.NewGormMDBByDB,
+		Option: &caskin.Option{
+			SuperAdminOption: &caskin.SuperAdminOption{Enable: true},
+		},
+	})
+}
@@ -0,0 +1,38 @@
+package caskin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// EntryCodec encode entry's id to string, and decode it back
+type EntryCodec interface {
+	Encode(uint64) string
+	Decode(string) (uint64, error)
+}
+
+// PrefixCodec encode id with a prefix, such as "role_1"
+type PrefixCodec string
+
+func (p PrefixCodec) Encode(id uint64) string {
+	return string(p) + strconv.FormatUint(id, 10)
+}
+
+func (p PrefixCodec) Decode(code string) (uint64, error) {
+	if !strings.HasPrefix(code, string(p)) {
+		return 0, fmt.Errorf("%w: %q has no prefix %q", ErrInvalidCode, code, string(p))
+	}
+
+	id, err := strconv.ParseUint(code[len(p):], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %q %v", ErrInvalidCode, code, err)
+	}
+
+	// reject such as "role_01" and "role_+1", which are not encoded from any id
+	if p.Encode(id) != code {
+		return 0, fmt.Errorf("%w: %q is not encoded as %q", ErrInvalidCode, code, p.Encode(id))
+	}
+
+	return id, nil
+}
@@ -0,0 +1,24 @@
+package caskin
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestPrefixCodec(t *testing.T) {
+	codec := PrefixCodec("role_")
+	for _, id := range []uint64{0, 1, 42, math.MaxUint64} {
+		code := codec.Encode(id)
+		got, err := codec.Decode(code)
+		if err != nil || got != id {
+			t.Fatalf("%v -> %q -> %v %v", id, code, got, err)
+		}
+	}
+
+	for _, code := range []string{"role_01", "role_+1", "role_", "role_1x", "user_1", "role_18446744073709551616"} {
+		if _, err := codec.Decode(code); !errors.Is(err, ErrInvalidCode) {
+			t.Fatalf("%q %v", code, err)
+		}
+	}
+}
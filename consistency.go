@@ -0,0 +1,230 @@
+package caskin
+
+import (
+	"fmt"
+	"strings"
+)
+
+type RuleIssueType string
+
+const (
+	// IssueOrphan the rule refers to an entry which is not in metadata database
+	IssueOrphan RuleIssueType = "orphan"
+	// IssueDangling the rule refers to an entry of another domain, or a code which can't be decoded
+	IssueDangling RuleIssueType = "dangling"
+	// IssueSoftDeleted the rule refers to a soft deleted entry, it is kept to recover the entry,
+	// so it is removed by repairing only on request
+	IssueSoftDeleted RuleIssueType = "soft_deleted"
+)
+
+// RuleIssue a casbin rule which is inconsistent with metadata database
+type RuleIssue struct {
+	Type   RuleIssueType
+	Rule   *Rule
+	Reason string
+	// if the rule is removed from casbin by repairing
+	Removed bool
+}
+
+// ConsistencyReport the result of checking casbin rules against metadata database
+type ConsistencyReport struct {
+	// count of p, g, g2 rules checked
+	Checked int
+	Issues  []*RuleIssue
+	// if the rules of all issues to repair are removed from casbin
+	Repaired bool
+}
+
+// IsEmpty if there is no inconsistent rule
+func (r *ConsistencyReport) IsEmpty() bool {
+	return len(r.Issues) == 0
+}
+
+// String one issue per line
+func (r *ConsistencyReport) String() string {
+	var lines []string
+	for _, v := range r.Issues {
+		lines = append(lines, fmt.Sprintf("%v %v %v: %v",
+			v.Type, v.Rule.PType, strings.Join(v.Rule.Values, ", "), v.Reason))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// issuesOf the issues whose rule is the ptype, the soft deleted ones are excluded unless it is asked
+func (r *ConsistencyReport) issuesOf(ptype string, softDeleted bool) []*RuleIssue {
+	var issues []*RuleIssue
+	for _, v := range r.Issues {
+		if v.Rule.PType == ptype && (softDeleted || v.Type != IssueSoftDeleted) {
+			issues = append(issues, v)
+		}
+	}
+	return issues
+}
+
+// consistencyChecker the snapshot of metadata database to check casbin rules,
+// the entries are keyed by their code
+type consistencyChecker struct {
+	factory EntryFactory
+	domains map[string]bool
+	// role's and object's code to its domain's code
+	roles   map[string]string
+	objects map[string]string
+	users   map[string]bool
+	// code of soft deleted domains, roles and objects
+	deleted map[string]bool
+}
+
+func newConsistencyChecker(factory EntryFactory) *consistencyChecker {
+	return &consistencyChecker{
+		factory: factory,
+		domains: map[string]bool{},
+		roles:   map[string]string{},
+		objects: map[string]string{},
+		users:   map[string]bool{},
+		deleted: map[string]bool{},
+	}
+}
+
+func (c *consistencyChecker) addDomain(domain Domain, roles []Role, objects []Object) {
+	c.domains[domain.Encode()] = true
+	for _, v := range roles {
+		c.roles[v.Encode()] = domain.Encode()
+	}
+	for _, v := range objects {
+		c.objects[v.Encode()] = domain.Encode()
+	}
+}
+
+func (c *consistencyChecker) addDeleted(entries ...entry) {
+	for _, v := range entries {
+		c.deleted[v.Encode()] = true
+	}
+}
+
+func (c *consistencyChecker) addUsers(users []User) {
+	for _, v := range users {
+		c.users[v.Encode()] = true
+	}
+}
+
+// check get the first issue of the rule, it is nil if the rule is consistent
+// 1. p is role, domain, object, action
+// 2. g is user or role, role, domain
+// 3. g is user, superadmin role, superadmin domain, or role, domain admin role, domain
+// 4. g2 is object, parent object, domain
+func (c *consistencyChecker) check(rule *Rule) *RuleIssue {
+	v := rule.Values
+	switch {
+	case rule.PType == PolicyPType && len(v) >= 4:
+		return c.firstIssue(func() *RuleIssue {
+			return c.checkDomain(rule, v[1])
+		}, func() *RuleIssue {
+			return c.checkRole(rule, v[0], v[1])
+		}, func() *RuleIssue {
+			return c.checkObject(rule, v[2], v[1])
+		})
+	case rule.PType == RolePType && len(v) >= 3 && v[1] == SuperadminRole && v[2] == SuperadminDomain:
+		return c.checkUser(rule, v[0])
+	case rule.PType == RolePType && len(v) >= 3 && v[1] == DomainAdminRole:
+		return c.firstIssue(func() *RuleIssue {
+			return c.checkDomain(rule, v[2])
+		}, func() *RuleIssue {
+			return c.checkRole(rule, v[0], v[2])
+		})
+	case rule.PType == RolePType && len(v) >= 3:
+		return c.firstIssue(func() *RuleIssue {
+			return c.checkDomain(rule, v[2])
+		}, func() *RuleIssue {
+			return c.checkRole(rule, v[1], v[2])
+		}, func() *RuleIssue {
+			if err := c.factory.NewUser().Decode(v[0]); err == nil {
+				return c.checkUser(rule, v[0])
+			}
+			return c.checkRole(rule, v[0], v[2])
+		})
+	case rule.PType == ObjectPType && len(v) >= 3:
+		return c.firstIssue(func() *RuleIssue {
+			return c.checkDomain(rule, v[2])
+		}, func() *RuleIssue {
+			return c.checkObject(rule, v[0], v[2])
+		}, func() *RuleIssue {
+			return c.checkObject(rule, v[1], v[2])
+		})
+	default:
+		return &RuleIssue{Type: IssueDangling, Rule: rule, Reason: "malformed rule"}
+	}
+}
+
+// firstIssue get the first orphan or dangling issue of the checks, or the first soft deleted issue if there is none,
+// so the rule to repair is not taken as soft deleted
+func (c *consistencyChecker) firstIssue(checks ...func() *RuleIssue) *RuleIssue {
+	var softDeleted *RuleIssue
+	for _, fn := range checks {
+		issue := fn()
+		if issue == nil {
+			continue
+		}
+		if issue.Type != IssueSoftDeleted {
+			return issue
+		}
+		if softDeleted == nil {
+			softDeleted = issue
+		}
+	}
+	return softDeleted
+}
+
+func (c *consistencyChecker) checkDomain(rule *Rule, code string) *RuleIssue {
+	if c.domains[code] {
+		return c.checkDeleted(rule, DomainEntry, code)
+	}
+	if err := c.factory.NewDomain().Decode(code); err != nil {
+		return &RuleIssue{Type: IssueDangling, Rule: rule, Reason: fmt.Sprintf("undecodable domain %v", code)}
+	}
+	return &RuleIssue{Type: IssueOrphan, Rule: rule, Reason: fmt.Sprintf("domain %v not exists", code)}
+}
+
+func (c *consistencyChecker) checkUser(rule *Rule, code string) *RuleIssue {
+	if c.users[code] {
+		return nil
+	}
+	if err := c.factory.NewUser().Decode(code); err != nil {
+		return &RuleIssue{Type: IssueDangling, Rule: rule, Reason: fmt.Sprintf("undecodable user %v", code)}
+	}
+	return &RuleIssue{Type: IssueOrphan, Rule: rule, Reason: fmt.Sprintf("user %v not exists", code)}
+}
+
+func (c *consistencyChecker) checkRole(rule *Rule, code, domain string) *RuleIssue {
+	if d, ok := c.roles[code]; ok {
+		return c.checkInDomain(rule, RoleEntry, code, d, domain)
+	}
+	if err := c.factory.NewRole().Decode(code); err != nil {
+		return &RuleIssue{Type: IssueDangling, Rule: rule, Reason: fmt.Sprintf("undecodable role %v", code)}
+	}
+	return &RuleIssue{Type: IssueOrphan, Rule: rule, Reason: fmt.Sprintf("role %v not exists", code)}
+}
+
+func (c *consistencyChecker) checkObject(rule *Rule, code, domain string) *RuleIssue {
+	if d, ok := c.objects[code]; ok {
+		return c.checkInDomain(rule, ObjectEntry, code, d, domain)
+	}
+	if err := c.factory.NewObject().Decode(code); err != nil {
+		return &RuleIssue{Type: IssueDangling, Rule: rule, Reason: fmt.Sprintf("undecodable object %v", code)}
+	}
+	return &RuleIssue{Type: IssueOrphan, Rule: rule, Reason: fmt.Sprintf("object %v not exists", code)}
+}
+
+func (c *consistencyChecker) checkDeleted(rule *Rule, kind EntryType, code string) *RuleIssue {
+	if !c.deleted[code] {
+		return nil
+	}
+	return &RuleIssue{Type: IssueSoftDeleted, Rule: rule, Reason: fmt.Sprintf("%v %v is soft deleted", kind, code)}
+}
+
+func (c *consistencyChecker) checkInDomain(rule *Rule, kind EntryType, code, entryDomain, domain string) *RuleIssue {
+	if entryDomain == domain {
+		return c.checkDeleted(rule, kind, code)
+	}
+	return &RuleIssue{Type: IssueDangling, Rule: rule, Reason: fmt.Sprintf("%v is in domain %v, not %v", code, entryDomain, domain)}
+}
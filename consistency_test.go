@@ -0,0 +1,85 @@
+package caskin_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+)
+
+type consistencyExecutor interface {
+	VerifyConsistency() (*caskin.ConsistencyReport, error)
+	RepairConsistency(bool) (*caskin.ConsistencyReport, error)
+}
+
+func TestRepairConsistency(t *testing.T) {
+	s := newStage(t, nil)
+	s.assign(t, 2, 2)
+	executor := s.executor(s.as(1)).(consistencyExecutor)
+	report, err := executor.VerifyConsistency()
+	if err != nil || !report.IsEmpty() {
+		t.Fatal(report, err)
+	}
+
+	// rules of soft deleted role and domain are kept to be recovered
+	if err := s.mdb.DeleteRoleByID(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.mdb.DeleteDomainByID(s.domain.ID); err != nil {
+		t.Fatal(err)
+	}
+	s.e.AddPolicy("role_9", s.domain.Encode(), "object_1", string(caskin.Read))
+	s.e.AddNamedGroupingPolicy("g2", "object_2", "object_1", "domain_9")
+	s.e.AddGroupingPolicy("user_2", "nobody", s.domain.Encode())
+
+	report, err = executor.VerifyConsistency()
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := map[caskin.RuleIssueType]int{}
+	for _, v := range report.Issues {
+		types[v.Type]++
+	}
+	// the soft deleted domain's p, g, g2 and user's g
+	if types[caskin.IssueOrphan] != 2 || types[caskin.IssueDangling] != 1 || types[caskin.IssueSoftDeleted] != 5 || report.Repaired {
+		t.Fatal(report)
+	}
+
+	report, err = executor.RepairConsistency(false)
+	if err != nil || !report.Repaired {
+		t.Fatal(report, err)
+	}
+	for _, v := range report.Issues {
+		if v.Removed != (v.Type != caskin.IssueSoftDeleted) {
+			t.Fatal(v)
+		}
+	}
+	if ok, _ := s.e.HasRoleForUser("user_2", "role_2", s.domain.Encode()); !ok {
+		t.Fatal("rule of soft deleted role is repaired away")
+	}
+	if !s.e.HasPolicy("role_1", s.domain.Encode(), "object_1", string(caskin.Write)) {
+		t.Fatal("rule of soft deleted domain is repaired away")
+	}
+	report, err = executor.VerifyConsistency()
+	if err != nil || len(report.Issues) != types[caskin.IssueSoftDeleted] {
+		t.Fatal(report, err)
+	}
+
+	// the rules of soft deleted entries are removed only on request
+	if report, err = executor.RepairConsistency(true); err != nil || !report.Repaired {
+		t.Fatal(report, err)
+	}
+	if report, err := executor.VerifyConsistency(); err != nil || !report.IsEmpty() {
+		t.Fatal(report, err)
+	}
+	if ok, _ := s.e.HasRoleForUser("user_2", "role_2", s.domain.Encode()); ok {
+		t.Fatal("rule of soft deleted role is not removed")
+	}
+}
+
+func TestVerifyConsistencyPermission(t *testing.T) {
+	s := newStage(t, nil)
+	if _, err := s.executor(s.as(2)).(consistencyExecutor).VerifyConsistency(); !errors.Is(err, caskin.ErrIsNotSuperAdmin) {
+		t.Fatal(err)
+	}
+}
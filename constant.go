@@ -1,8 +1,12 @@
 package caskin
 
 const (
+	PolicyPType = "p"
+	RolePType   = "g"
 	ObjectPType = "g2"
 
 	SuperadminRole   = "superadmin"
 	SuperadminDomain = "superdomain"
+
+	DomainAdminRole = "domainadmin"
 )
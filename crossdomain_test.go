@@ -0,0 +1,74 @@
+package caskin_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+type crossDomainExecutor interface {
+	CreateDomain(caskin.Domain) error
+	DeleteRole(caskin.Role, ...caskin.DeleteOption) error
+	ExportDomain(caskin.Domain) (*caskin.DomainArchive, error)
+	ImportDomain(*caskin.DomainArchive, caskin.Domain) (*caskin.DomainImport, error)
+	CopyAccess(caskin.User, caskin.User) error
+}
+
+// newCrossDomainStage the stage with domain 2 of role 3, 4 and object 3, 4
+func newCrossDomainStage(t *testing.T) (*stage, *example.Domain) {
+	s := newStage(t, nil)
+	other := &example.Domain{Name: "domain_2"}
+	if err := s.executor(s.as(1)).(crossDomainExecutor).CreateDomain(other); err != nil {
+		t.Fatal(err)
+	}
+	return s, other
+}
+
+func TestCrossDomainRole(t *testing.T) {
+	s, _ := newCrossDomainStage(t)
+	executor := s.executor(s.as(1)).(crossDomainExecutor)
+	if err := executor.DeleteRole(&example.Role{ID: 3}); !errors.Is(err, caskin.ErrCrossDomain) {
+		t.Fatal(err)
+	}
+
+	// user 2 is assigned to role 3 of domain 2 in domain 1, it can't be copied
+	s.e.AddGroupingPolicy("user_2", "role_3", s.domain.Encode())
+	if err := executor.CopyAccess(&example.User{ID: 4}, &example.User{ID: 2}); !errors.Is(err, caskin.ErrCrossDomain) {
+		t.Fatal(err)
+	}
+	if ok, _ := s.e.HasRoleForUser("user_4", "role_3", s.domain.Encode()); ok {
+		t.Fatal("role of other domain is copied")
+	}
+}
+
+func TestCrossDomainImport(t *testing.T) {
+	s, other := newCrossDomainStage(t)
+	s.assign(t, 3, 1)
+	archive, err := s.executor(s.as(1)).(crossDomainExecutor).ExportDomain(s.domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// only superadmin could import into the domain which is not current domain
+	if _, err := s.executor(s.as(3)).(crossDomainExecutor).ImportDomain(archive, other); !errors.Is(err, caskin.ErrCrossDomain) {
+		t.Fatal(err)
+	}
+
+	// the archive's role is not in the archive's domain
+	archive.Roles[0].SetDomainID(other.ID)
+	if _, err := s.executor(s.as(1)).(crossDomainExecutor).ImportDomain(archive, other); !errors.Is(err, caskin.ErrCrossDomain) {
+		t.Fatal(err)
+	}
+
+	// the archive could be imported into an empty domain then
+	archive.Roles[0].SetDomainID(s.domain.ID)
+	empty := &example.Domain{Name: "domain_3"}
+	if err := s.db.Create(empty).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.executor(s.as(1)).(crossDomainExecutor).ImportDomain(archive, empty); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,70 @@
+package caskin
+
+import (
+	"fmt"
+)
+
+// DeleteOption how to deal with the dependents when deleting an entry.
+// default is to remove the entry's own g, g2 and p only
+type DeleteOption string
+
+const (
+	// DeleteRestrict fail with DependentsError if there is any dependent
+	DeleteRestrict DeleteOption = "restrict"
+	// DeleteCascade delete the dependents recursively
+	DeleteCascade DeleteOption = "cascade"
+	// DeleteDetach re parent the children to the entry's parent, it is invalid for domain which has no parent
+	DeleteDetach DeleteOption = "detach"
+)
+
+// DependentsError the entry to delete with DeleteRestrict still has dependents
+type DependentsError struct {
+	// code of the entry to delete
+	Code string
+	// users of the role, or members of the domain
+	Users []User
+	// child roles of the role
+	Roles []Role
+	// child objects of the object
+	Objects []Object
+	// policies of the object
+	Policies []*Policy
+}
+
+func (d *DependentsError) Error() string {
+	return fmt.Sprintf("%v: %v has %v users, %v roles, %v objects, %v policies",
+		ErrHasDependents, d.Code, len(d.Users), len(d.Roles), len(d.Objects), len(d.Policies))
+}
+
+func (d *DependentsError) Unwrap() error {
+	return ErrHasDependents
+}
+
+func (d *DependentsError) isEmpty() bool {
+	return len(d.Users)+len(d.Roles)+len(d.Objects)+len(d.Policies) == 0
+}
+
+// getDeleteOption get the first DeleteOption, it is ErrInvalidDeleteOption if it is unknown
+func getDeleteOption(option []DeleteOption) (DeleteOption, error) {
+	if len(option) == 0 {
+		return "", nil
+	}
+
+	switch option[0] {
+	case "", DeleteRestrict, DeleteCascade, DeleteDetach:
+		return option[0], nil
+	default:
+		return "", fmt.Errorf("%w: %v", ErrInvalidDeleteOption, option[0])
+	}
+}
+
+// getChildrenID get children's id of the parent in the tree of child to parent
+func getChildrenID(tree map[uint64]uint64, parentID uint64) []uint64 {
+	var id []uint64
+	for k, v := range tree {
+		if v == parentID {
+			id = append(id, k)
+		}
+	}
+	return id
+}
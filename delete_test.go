@@ -0,0 +1,231 @@
+package caskin_test
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+type deleteExecutor interface {
+	DeleteRole(caskin.Role, ...caskin.DeleteOption) error
+	DeleteObject(caskin.Object, ...caskin.DeleteOption) error
+	DeleteDomain(caskin.Domain, ...caskin.DeleteOption) error
+}
+
+func TestDeleteOptionInvalid(t *testing.T) {
+	s := newStage(t, nil)
+	executor := s.executor(s.as(1)).(deleteExecutor)
+	if err := executor.DeleteRole(&example.Role{ID: 2}, "orphan"); !errors.Is(err, caskin.ErrInvalidDeleteOption) {
+		t.Fatal(err)
+	}
+	if err := executor.DeleteObject(&example.Object{ID: 2}, "orphan"); !errors.Is(err, caskin.ErrInvalidDeleteOption) {
+		t.Fatal(err)
+	}
+	if err := executor.DeleteDomain(s.domain, "orphan"); !errors.Is(err, caskin.ErrInvalidDeleteOption) {
+		t.Fatal(err)
+	}
+	// domain has no parent to detach its children to
+	if err := executor.DeleteDomain(s.domain, caskin.DeleteDetach); !errors.Is(err, caskin.ErrInvalidDeleteOption) {
+		t.Fatal(err)
+	}
+	if caskin.GetHTTPStatus(caskin.ErrInvalidDeleteOption) != http.StatusBadRequest {
+		t.Fatal(caskin.GetHTTPStatus(caskin.ErrInvalidDeleteOption))
+	}
+	if err := s.mdb.TakeDomain(&example.Domain{ID: s.domain.ID}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDeleteDomainRestrict(t *testing.T) {
+	s := newStage(t, nil)
+	s.assign(t, 2, 2)
+	err := s.executor(s.as(1)).(deleteExecutor).DeleteDomain(s.domain, caskin.DeleteRestrict)
+	var dependents *caskin.DependentsError
+	if !errors.As(err, &dependents) {
+		t.Fatal(err)
+	}
+	if len(dependents.Users) != 1 || len(dependents.Roles) != 2 || len(dependents.Objects) != 2 {
+		t.Fatal(dependents)
+	}
+}
+
+func TestDeleteDomainCascade(t *testing.T) {
+	s := newStage(t, nil)
+	s.assign(t, 3, 1)
+
+	// user 3 could write root, but not the roles without object
+	var perr *caskin.PermissionError
+	if err := s.executor(s.as(3)).(deleteExecutor).DeleteDomain(s.domain, caskin.DeleteCascade); !errors.As(err, &perr) {
+		t.Fatal(err)
+	}
+	if ok, _ := s.e.HasRoleForUser("user_3", "role_1", s.domain.Encode()); !ok {
+		t.Fatal("user's g is deleted before the permission is checked")
+	}
+	var n int64
+	if err := s.db.Model(&example.Role{}).Count(&n).Error; err != nil || n != 2 {
+		t.Fatal(n, err)
+	}
+
+	if err := s.executor(s.as(1)).(deleteExecutor).DeleteDomain(s.domain, caskin.DeleteCascade); err != nil {
+		t.Fatal(err)
+	}
+	var roles, objects int64
+	s.db.Model(&example.Role{}).Count(&roles)
+	s.db.Model(&example.Object{}).Count(&objects)
+	if roles != 0 || objects != 0 || len(s.e.GetPolicy()) != 0 {
+		t.Fatal(roles, objects, s.e.GetPolicy())
+	}
+}
+
+// newDeleteTreeStage add role_3 under member and object_3 under data which user 3 as admin could write,
+// and role_4 under admin and object_4 under root whose object is empty, so only superadmin could write them
+func newDeleteTreeStage(t *testing.T) *stage {
+	s := newStage(t, nil)
+	s.assign(t, 3, 1)
+	for _, v := range []interface{}{
+		&example.Role{ID: 1, Object: "object_1"},
+		&example.Role{ID: 2, Object: "object_1"},
+		&example.Object{ID: 1, Object: "object_1"},
+		&example.Object{ID: 2, Object: "object_1"},
+	} {
+		if err := s.db.Updates(v).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, v := range []interface{}{
+		&example.Role{ID: 3, Name: "guest", Object: "object_1", DomainID: s.domain.ID},
+		&example.Role{ID: 4, Name: "secret", DomainID: s.domain.ID},
+		&example.Object{ID: 3, Name: "sheet", Type: example.ObjectTypeDefault, Object: "object_1", DomainID: s.domain.ID},
+		&example.Object{ID: 4, Name: "secret", Type: example.ObjectTypeDefault, DomainID: s.domain.ID},
+	} {
+		if err := s.db.Create(v).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	s.e.AddGroupingPolicy("role_2", "role_3", s.domain.Encode())
+	s.e.AddGroupingPolicy("role_1", "role_4", s.domain.Encode())
+	s.e.AddNamedGroupingPolicy("g2", "object_3", "object_2", s.domain.Encode())
+	s.e.AddNamedGroupingPolicy("g2", "object_4", "object_1", s.domain.Encode())
+	return s
+}
+
+func (s *stage) count(t *testing.T, model interface{}) int64 {
+	var n int64
+	if err := s.db.Model(model).Count(&n).Error; err != nil {
+		t.Fatal(err)
+	}
+	return n
+}
+
+func TestDeleteRoleRestrict(t *testing.T) {
+	s := newDeleteTreeStage(t)
+	err := s.executor(s.as(1)).(deleteExecutor).DeleteRole(&example.Role{ID: 1}, caskin.DeleteRestrict)
+	var dependents *caskin.DependentsError
+	if !errors.As(err, &dependents) {
+		t.Fatal(err)
+	}
+	if len(dependents.Users) != 1 || len(dependents.Roles) != 2 {
+		t.Fatal(dependents)
+	}
+	if n := s.count(t, &example.Role{}); n != 4 {
+		t.Fatal(n)
+	}
+}
+
+func TestDeleteRoleCascade(t *testing.T) {
+	s := newDeleteTreeStage(t)
+
+	// user 3 could write member and guest, but not secret, so nothing is deleted
+	var perr *caskin.PermissionError
+	if err := s.executor(s.as(3)).(deleteExecutor).DeleteRole(&example.Role{ID: 1}, caskin.DeleteCascade); !errors.As(err, &perr) {
+		t.Fatal(err)
+	}
+	if n := s.count(t, &example.Role{}); n != 4 {
+		t.Fatal("roles are deleted before all of them are checked", n)
+	}
+	if ok, _ := s.e.HasRoleForUser("role_2", "role_3", s.domain.Encode()); !ok {
+		t.Fatal("g is deleted before all of them are checked")
+	}
+
+	// the whole subtree is deleted with its rules
+	if err := s.executor(s.as(1)).(deleteExecutor).DeleteRole(&example.Role{ID: 1}, caskin.DeleteCascade); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.count(t, &example.Role{}); n != 0 {
+		t.Fatal(n)
+	}
+	if len(s.e.GetFilteredGroupingPolicy(2, s.domain.Encode())) != 0 {
+		t.Fatal(s.e.GetFilteredGroupingPolicy(2, s.domain.Encode()))
+	}
+}
+
+func TestDeleteRoleDetach(t *testing.T) {
+	s := newDeleteTreeStage(t)
+	if err := s.executor(s.as(3)).(deleteExecutor).DeleteRole(&example.Role{ID: 2}, caskin.DeleteDetach); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.mdb.TakeRole(&example.Role{ID: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := s.e.HasRoleForUser("role_1", "role_3", s.domain.Encode()); !ok {
+		t.Fatal("guest is not detached to admin")
+	}
+}
+
+func TestDeleteObjectRestrict(t *testing.T) {
+	s := newDeleteTreeStage(t)
+	err := s.executor(s.as(1)).(deleteExecutor).DeleteObject(&example.Object{ID: 2}, caskin.DeleteRestrict)
+	var dependents *caskin.DependentsError
+	if !errors.As(err, &dependents) {
+		t.Fatal(err)
+	}
+	if len(dependents.Objects) != 1 || len(dependents.Policies) != 0 {
+		t.Fatal(dependents)
+	}
+	if n := s.count(t, &example.Object{}); n != 4 {
+		t.Fatal(n)
+	}
+}
+
+func TestDeleteObjectCascade(t *testing.T) {
+	s := newDeleteTreeStage(t)
+
+	// user 3 could write data and sheet, but not secret, so nothing is deleted
+	var perr *caskin.PermissionError
+	if err := s.executor(s.as(3)).(deleteExecutor).DeleteObject(&example.Object{ID: 1}, caskin.DeleteCascade); !errors.As(err, &perr) {
+		t.Fatal(err)
+	}
+	if n := s.count(t, &example.Object{}); n != 4 {
+		t.Fatal("objects are deleted before all of them are checked", n)
+	}
+	if !s.e.HasNamedGroupingPolicy("g2", "object_3", "object_2", s.domain.Encode()) {
+		t.Fatal("g2 is deleted before all of them are checked")
+	}
+
+	// the whole subtree is deleted with its rules
+	if err := s.executor(s.as(1)).(deleteExecutor).DeleteObject(&example.Object{ID: 1}, caskin.DeleteCascade); err != nil {
+		t.Fatal(err)
+	}
+	if n := s.count(t, &example.Object{}); n != 0 {
+		t.Fatal(n)
+	}
+	if len(s.e.GetNamedGroupingPolicy("g2")) != 0 || len(s.e.GetPolicy()) != 0 {
+		t.Fatal(s.e.GetNamedGroupingPolicy("g2"), s.e.GetPolicy())
+	}
+}
+
+func TestDeleteObjectDetach(t *testing.T) {
+	s := newDeleteTreeStage(t)
+	if err := s.executor(s.as(3)).(deleteExecutor).DeleteObject(&example.Object{ID: 2}, caskin.DeleteDetach); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.mdb.TakeObject(&example.Object{ID: 3}); err != nil {
+		t.Fatal(err)
+	}
+	if !s.e.HasNamedGroupingPolicy("g2", "object_3", "object_1", s.domain.Encode()) {
+		t.Fatal("sheet is not detached to root")
+	}
+}
@@ -0,0 +1,66 @@
+package caskin_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+type domainAdminExecutor interface {
+	AddDomainAdminUser(caskin.User) error
+	DeleteDomainAdminUser(caskin.User) error
+	GetAllDomainAdminUser() ([]caskin.User, error)
+}
+
+// pickAdmin pick the stageCreator's admin role as domain admin role
+func pickAdmin(roles []caskin.Role) caskin.Role {
+	for _, v := range roles {
+		if v.(*example.Role).Name == "admin" {
+			return v
+		}
+	}
+	return nil
+}
+
+func TestDomainAdmin(t *testing.T) {
+	s := newStage(t, &caskin.Option{DomainAdminOption: &caskin.DomainAdminOption{Enable: true, Role: pickAdmin}})
+	if roles := s.e.GetUsersForRoleInDomain(caskin.DomainAdminRole, s.domain.Encode()); len(roles) != 1 || roles[0] != "role_1" {
+		t.Fatal(roles)
+	}
+
+	executor := s.executor(s.as(1)).(domainAdminExecutor)
+	if err := executor.AddDomainAdminUser(&example.User{ID: 2}); err != nil {
+		t.Fatal(err)
+	}
+	users, err := executor.GetAllDomainAdminUser()
+	if err != nil || len(users) != 1 || users[0].GetID() != 2 {
+		t.Fatal(users, err)
+	}
+	if ok, _ := s.e.Enforce("user_2", s.domain.Encode(), "object_9", string(caskin.Write)); !ok {
+		t.Fatal("domain admin can't write in its domain")
+	}
+	if ok, _ := s.e.Enforce("user_2", "domain_2", "object_9", string(caskin.Write)); ok {
+		t.Fatal("domain admin can write out of its domain")
+	}
+
+	if err := executor.DeleteDomainAdminUser(&example.User{ID: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := s.e.Enforce("user_2", s.domain.Encode(), "object_9", string(caskin.Write)); ok {
+		t.Fatal("deleted domain admin can write")
+	}
+}
+
+func TestDomainAdminOption(t *testing.T) {
+	s := newStage(t, nil)
+	if _, err := s.executor(s.as(1)).(domainAdminExecutor).GetAllDomainAdminUser(); !errors.Is(err, caskin.ErrDomainAdminIsNoEnabled) {
+		t.Fatal(err)
+	}
+
+	option := &caskin.Option{DomainAdminOption: &caskin.DomainAdminOption{Enable: true}}
+	if _, err := caskin.New(s.mdb, s.e, example.NewEntryFactory(), option); !errors.Is(err, caskin.ErrInvalidDomainAdmin) {
+		t.Fatal(err)
+	}
+}
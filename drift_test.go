@@ -0,0 +1,82 @@
+package caskin_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+type driftExecutor interface {
+	CheckDomainDrift(caskin.Domain) (*caskin.DomainDrift, error)
+}
+
+func TestCheckDomainDrift(t *testing.T) {
+	option := &caskin.Option{DomainCreator: stageCreator, TemplateKeyFields: []string{"name"}, CompareFields: []string{"object"}}
+	s := newStage(t, option)
+	option.DomainTemplates = []*caskin.DomainTemplate{{Version: 1, Creator: viewerCreator}}
+	if err := s.db.Create(&example.Role{Name: "custom", DomainID: s.domain.ID}).Error; err != nil {
+		t.Fatal(err)
+	}
+
+	drift, err := s.executor(s.as(1)).(driftExecutor).CheckDomainDrift(&example.Domain{ID: s.domain.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drift.ModifiedRoles) != 1 || drift.ModifiedRoles[0].Live.GetID() != 1 || len(drift.ModifiedObjects) != 0 {
+		t.Fatal(drift.ModifiedRoles, drift.ModifiedObjects)
+	}
+	if len(drift.Missing.Roles) != 1 || len(drift.Missing.Objects) != 0 || len(drift.Missing.Policies) != 1 {
+		t.Fatal(drift.Missing)
+	}
+	// member and custom, and member's policy
+	if len(drift.Extra.Roles) != 2 || len(drift.Extra.Objects) != 0 || len(drift.Extra.Policies) != 1 {
+		t.Fatal(drift.Extra)
+	}
+}
+
+func TestCheckDomainDriftPermission(t *testing.T) {
+	s := newStage(t, nil)
+	other := &example.Domain{Name: "domain_2"}
+	if err := s.db.Create(other).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.executor(s.as(2)).(driftExecutor).CheckDomainDrift(other); !errors.Is(err, caskin.ErrCrossDomain) {
+		t.Fatal(err)
+	}
+
+	drift, err := s.executor(s.as(2)).(driftExecutor).CheckDomainDrift(&example.Domain{ID: s.domain.ID})
+	if err != nil || !drift.IsEmpty() {
+		t.Fatal(drift, err)
+	}
+}
+
+func TestCheckDomainDriftDefault(t *testing.T) {
+	option := &caskin.Option{DomainCreator: stageCreator}
+	s := newStage(t, option)
+	// member is renamed in the domain, so it is extra and template's member is missing,
+	// and admin's object differs from the template
+	for _, v := range []interface{}{
+		&example.Role{ID: 1, Object: "object_1"},
+		&example.Role{ID: 2, Name: "staff"},
+	} {
+		if err := s.db.Updates(v).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	drift, err := s.executor(s.as(1)).(driftExecutor).CheckDomainDrift(&example.Domain{ID: s.domain.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(drift.ModifiedRoles) != 1 || drift.ModifiedRoles[0].Live.GetID() != 1 || len(drift.ModifiedObjects) != 0 {
+		t.Fatal(drift.ModifiedRoles, drift.ModifiedObjects)
+	}
+	if len(drift.Missing.Roles) != 1 || drift.Missing.Roles[0].(*example.Role).Name != "member" {
+		t.Fatal(drift.Missing)
+	}
+	if len(drift.Extra.Roles) != 1 || drift.Extra.Roles[0].GetID() != 2 {
+		t.Fatal(drift.Extra)
+	}
+}
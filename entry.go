@@ -9,6 +9,15 @@ const (
 	Write Action = "write"
 )
 
+type EntryType string
+
+const (
+	UserEntry   EntryType = "user"
+	RoleEntry   EntryType = "role"
+	ObjectEntry EntryType = "object"
+	DomainEntry EntryType = "domain"
+)
+
 type Policy struct {
 	Role   Role
 	Object Object
@@ -41,6 +50,11 @@ type entry interface {
 	GetObject() string
 }
 
+// Entry the methods shared by User, Role, Object and Domain
+type Entry interface {
+	entry
+}
+
 type parent interface {
 	// get parent id method
 	GetParentID() uint64
@@ -49,6 +63,8 @@ type parent interface {
 }
 
 type inDomain interface {
+	// get domain id method
+	GetDomainID() uint64
 	// set domain id method
 	SetDomainID(uint64)
 }
@@ -77,6 +93,19 @@ type Domain interface {
 	entry
 }
 
+// ObjectSetter the role or object which could set its object code,
+// it is required to import or clone a domain whose roles or objects have object code
+type ObjectSetter interface {
+	SetObject(string)
+}
+
+// VersionedDomain the domain which keeps its DomainTemplate's version in metadata database
+type VersionedDomain interface {
+	Domain
+	GetTemplateVersion() uint64
+	SetTemplateVersion(uint64)
+}
+
 type EntryFactory interface {
 	NewUser() User
 	NewRole() Role
@@ -7,10 +7,90 @@ var (
 	ErrEmptyID       = fmt.Errorf("empty id")
 	ErrAlreadyExists = fmt.Errorf("already exists")
 	ErrNotExists     = fmt.Errorf("not exists")
+	ErrCrossDomain   = fmt.Errorf("not in current domain")
+
+	ErrInvalidEntryType    = fmt.Errorf("invalid entry type")
+	ErrHasDependents       = fmt.Errorf("has dependents")
+	ErrInvalidDeleteOption = fmt.Errorf("invalid delete option")
 
 	ErrNoReadPermission  = fmt.Errorf("no read permission")
 	ErrNoWritePermission = fmt.Errorf("no write permission")
 
 	ErrIsNotSuperAdmin       = fmt.Errorf("is no superadmin")
 	ErrSuperAdminIsNoEnabled = fmt.Errorf("superadmin is not enabled ")
+	ErrInvalidSuperadmin     = fmt.Errorf("invalid superadmin option")
+
+	ErrDomainAdminIsNoEnabled   = fmt.Errorf("domain admin is not enabled")
+	ErrDomainAdminRoleNotExists = fmt.Errorf("domain admin role not exists")
+	ErrInvalidDomainAdmin       = fmt.Errorf("invalid domain admin option")
+
+	ErrInvalidCode         = fmt.Errorf("invalid code")
+	ErrInvalidEntryCodec   = fmt.Errorf("invalid entry codec")
+	ErrEntryCodecCollision = fmt.Errorf("entry codec collision")
+
+	ErrInvalidDomainSpec    = fmt.Errorf("invalid domain spec")
+	ErrDomainIsNotVersioned = fmt.Errorf("domain is not versioned")
+
+	ErrTrashIsNotSupported = fmt.Errorf("trash is not supported by metadata database")
+
+	// errors of the request served by caskinhttp and caskingrpc
+	ErrInvalidRequest = fmt.Errorf("invalid request")
+	ErrUnauthorized   = fmt.Errorf("unauthorized")
+	ErrForbidden      = fmt.Errorf("forbidden")
 )
+
+// PermissionError current user has no permission to do the action on the entry in the domain,
+// it is ErrNoReadPermission for Read and ErrNoWritePermission for others by errors.Is
+type PermissionError struct {
+	User   User
+	Domain Domain
+	// code of the entry
+	Entry  string
+	Action Action
+}
+
+func (p *PermissionError) Error() string {
+	return fmt.Sprintf("%v: %v can't %v %v in %v", p.Unwrap(), encodeOrNil(p.User), p.Action, p.Entry, encodeOrNil(p.Domain))
+}
+
+func (p *PermissionError) Unwrap() error {
+	if p.Action == Read {
+		return ErrNoReadPermission
+	}
+	return ErrNoWritePermission
+}
+
+// NotFoundError the entry is not in metadata database, it is ErrNotExists by errors.Is
+type NotFoundError struct {
+	Kind EntryType
+	ID   uint64
+}
+
+func (n *NotFoundError) Error() string {
+	return fmt.Sprintf("%v: %v %v", ErrNotExists, n.Kind, n.ID)
+}
+
+func (n *NotFoundError) Unwrap() error {
+	return ErrNotExists
+}
+
+// ConflictError the entry already exists in metadata database, it is ErrAlreadyExists by errors.Is
+type ConflictError struct {
+	Kind EntryType
+	ID   uint64
+}
+
+func (c *ConflictError) Error() string {
+	return fmt.Sprintf("%v: %v %v", ErrAlreadyExists, c.Kind, c.ID)
+}
+
+func (c *ConflictError) Unwrap() error {
+	return ErrAlreadyExists
+}
+
+func encodeOrNil(one entry) string {
+	if one == nil {
+		return "<nil>"
+	}
+	return one.Encode()
+}
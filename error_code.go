@@ -0,0 +1,109 @@
+package caskin
+
+import (
+	"errors"
+	"net/http"
+)
+
+// ErrorCode the stable machine readable code of caskin's error
+type ErrorCode string
+
+const (
+	CodeOK                       ErrorCode = ""
+	CodeUnknown                  ErrorCode = "unknown"
+	CodeNil                      ErrorCode = "nil"
+	CodeEmptyID                  ErrorCode = "empty_id"
+	CodeAlreadyExists            ErrorCode = "already_exists"
+	CodeNotExists                ErrorCode = "not_exists"
+	CodeCrossDomain              ErrorCode = "cross_domain"
+	CodeInvalidEntryType         ErrorCode = "invalid_entry_type"
+	CodeHasDependents            ErrorCode = "has_dependents"
+	CodeInvalidDeleteOption      ErrorCode = "invalid_delete_option"
+	CodeNoReadPermission         ErrorCode = "no_read_permission"
+	CodeNoWritePermission        ErrorCode = "no_write_permission"
+	CodeIsNotSuperAdmin          ErrorCode = "is_not_superadmin"
+	CodeSuperAdminIsNotEnabled   ErrorCode = "superadmin_is_not_enabled"
+	CodeInvalidSuperadmin        ErrorCode = "invalid_superadmin"
+	CodeDomainAdminIsNotEnabled  ErrorCode = "domain_admin_is_not_enabled"
+	CodeDomainAdminRoleNotExists ErrorCode = "domain_admin_role_not_exists"
+	CodeInvalidDomainAdmin       ErrorCode = "invalid_domain_admin"
+	CodeInvalidCode              ErrorCode = "invalid_code"
+	CodeInvalidEntryCodec        ErrorCode = "invalid_entry_codec"
+	CodeEntryCodecCollision      ErrorCode = "entry_codec_collision"
+	CodeInvalidDomainSpec        ErrorCode = "invalid_domain_spec"
+	CodeTrashIsNotSupported      ErrorCode = "trash_is_not_supported"
+	CodeInvalidRequest           ErrorCode = "invalid_request"
+	CodeUnauthorized             ErrorCode = "unauthorized"
+	CodeForbidden                ErrorCode = "forbidden"
+)
+
+type errorMapping struct {
+	err    error
+	code   ErrorCode
+	status int
+}
+
+// errorMappings the sentinel errors to code and HTTP status, typed errors are matched by errors.Is
+var errorMappings = []*errorMapping{
+	{ErrNil, CodeNil, http.StatusBadRequest},
+	{ErrEmptyID, CodeEmptyID, http.StatusBadRequest},
+	{ErrAlreadyExists, CodeAlreadyExists, http.StatusConflict},
+	{ErrNotExists, CodeNotExists, http.StatusNotFound},
+	{ErrCrossDomain, CodeCrossDomain, http.StatusForbidden},
+	{ErrInvalidEntryType, CodeInvalidEntryType, http.StatusBadRequest},
+	{ErrHasDependents, CodeHasDependents, http.StatusConflict},
+	{ErrInvalidDeleteOption, CodeInvalidDeleteOption, http.StatusBadRequest},
+	{ErrNoReadPermission, CodeNoReadPermission, http.StatusForbidden},
+	{ErrNoWritePermission, CodeNoWritePermission, http.StatusForbidden},
+	{ErrIsNotSuperAdmin, CodeIsNotSuperAdmin, http.StatusForbidden},
+	{ErrSuperAdminIsNoEnabled, CodeSuperAdminIsNotEnabled, http.StatusNotImplemented},
+	{ErrInvalidSuperadmin, CodeInvalidSuperadmin, http.StatusInternalServerError},
+	{ErrDomainAdminIsNoEnabled, CodeDomainAdminIsNotEnabled, http.StatusNotImplemented},
+	{ErrDomainAdminRoleNotExists, CodeDomainAdminRoleNotExists, http.StatusPreconditionFailed},
+	{ErrInvalidDomainAdmin, CodeInvalidDomainAdmin, http.StatusInternalServerError},
+	{ErrInvalidCode, CodeInvalidCode, http.StatusBadRequest},
+	{ErrInvalidEntryCodec, CodeInvalidEntryCodec, http.StatusInternalServerError},
+	{ErrEntryCodecCollision, CodeEntryCodecCollision, http.StatusInternalServerError},
+	{ErrInvalidDomainSpec, CodeInvalidDomainSpec, http.StatusBadRequest},
+	{ErrTrashIsNotSupported, CodeTrashIsNotSupported, http.StatusNotImplemented},
+	{ErrInvalidRequest, CodeInvalidRequest, http.StatusBadRequest},
+	{ErrUnauthorized, CodeUnauthorized, http.StatusUnauthorized},
+	{ErrForbidden, CodeForbidden, http.StatusForbidden},
+}
+
+var unknownErrorMapping = &errorMapping{nil, CodeUnknown, http.StatusInternalServerError}
+
+// GetErrorCode get the error's code, it is CodeOK for nil and CodeUnknown for not caskin's error
+func GetErrorCode(err error) ErrorCode {
+	if err == nil {
+		return CodeOK
+	}
+	return getErrorMapping(err).code
+}
+
+// GetHTTPStatus get the error's HTTP status, it is 200 for nil and 500 for not caskin's error
+func GetHTTPStatus(err error) int {
+	if err == nil {
+		return http.StatusOK
+	}
+	return getErrorMapping(err).status
+}
+
+// GetErrorByCode get the sentinel error of the code, it is nil if there is no such code
+func GetErrorByCode(code ErrorCode) error {
+	for _, v := range errorMappings {
+		if v.code == code {
+			return v.err
+		}
+	}
+	return nil
+}
+
+func getErrorMapping(err error) *errorMapping {
+	for _, v := range errorMappings {
+		if errors.Is(err, v.err) {
+			return v
+		}
+	}
+	return unknownErrorMapping
+}
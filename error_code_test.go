@@ -0,0 +1,44 @@
+package caskin_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+func TestErrorCode(t *testing.T) {
+	permission := &caskin.PermissionError{User: &example.User{ID: 2}, Entry: "role_1", Action: caskin.Write}
+	if !errors.Is(permission, caskin.ErrNoWritePermission) || caskin.GetErrorCode(permission) != caskin.CodeNoWritePermission {
+		t.Fatal(permission)
+	}
+	if permission.Error() != "no write permission: user_2 can't write role_1 in <nil>" {
+		t.Fatal(permission.Error())
+	}
+
+	notFound := fmt.Errorf("wrapped: %w", &caskin.NotFoundError{Kind: caskin.RoleEntry, ID: 9})
+	if caskin.GetErrorCode(notFound) != caskin.CodeNotExists || caskin.GetHTTPStatus(notFound) != http.StatusNotFound {
+		t.Fatal(notFound)
+	}
+	if caskin.GetErrorCode(nil) != caskin.CodeOK || caskin.GetHTTPStatus(nil) != http.StatusOK {
+		t.Fatal("nil is not ok")
+	}
+	if unknown := fmt.Errorf("database is gone"); caskin.GetErrorCode(unknown) != caskin.CodeUnknown || caskin.GetHTTPStatus(unknown) != http.StatusInternalServerError {
+		t.Fatal(unknown)
+	}
+	if caskin.GetErrorByCode(caskin.CodeCrossDomain) != caskin.ErrCrossDomain || caskin.GetErrorByCode("nothing") != nil {
+		t.Fatal(caskin.GetErrorByCode(caskin.CodeCrossDomain))
+	}
+}
+
+func TestErrorCodeDomainAdminUser(t *testing.T) {
+	s := newStage(t, &caskin.Option{DomainAdminOption: &caskin.DomainAdminOption{Enable: true, Role: pickAdmin}})
+	err := s.executor(s.as(1)).(domainAdminExecutor).AddDomainAdminUser(&example.User{ID: 9})
+	var notFound *caskin.NotFoundError
+	if !errors.As(err, &notFound) || notFound.Kind != caskin.UserEntry || notFound.ID != 9 {
+		t.Fatal(err)
+	}
+}
@@ -9,11 +9,12 @@ import (
 
 // Domain sample for caskin.Domain interface
 type Domain struct {
-	ID        uint64         `gorm:"column:id;primaryKey"   json:"id,omitempty"`
-	CreatedAt time.Time      `gorm:"column:created_at"      json:"created_at,omitempty"`
-	UpdatedAt time.Time      `gorm:"column:updated_at"      json:"updated_at,omitempty"`
-	DeletedAt gorm.DeletedAt `gorm:"column:delete_at;index" json:"-"`
-	Name      string         `gorm:"column:name;unique"     json:"name,omitempty"`
+	ID              uint64         `gorm:"column:id;primaryKey"    json:"id,omitempty"`
+	CreatedAt       time.Time      `gorm:"column:created_at"       json:"created_at,omitempty"`
+	UpdatedAt       time.Time      `gorm:"column:updated_at"       json:"updated_at,omitempty"`
+	DeletedAt       gorm.DeletedAt `gorm:"column:delete_at;index"  json:"-"`
+	Name            string         `gorm:"column:name;unique"      json:"name,omitempty"`
+	TemplateVersion uint64         `gorm:"column:template_version" json:"template_version,omitempty"`
 }
 
 func (d *Domain) GetID() uint64 {
@@ -40,3 +41,11 @@ func (d *Domain) IsObject() bool {
 func (d *Domain) GetObject() string {
 	return ""
 }
+
+func (d *Domain) GetTemplateVersion() uint64 {
+	return d.TemplateVersion
+}
+
+func (d *Domain) SetTemplateVersion(version uint64) {
+	d.TemplateVersion = version
+}
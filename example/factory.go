@@ -0,0 +1,27 @@
+package example
+
+import "github.com/awatercolorpen/caskin"
+
+type factory struct {
+}
+
+// NewEntryFactory the EntryFactory of the example's User, Role, Object and Domain
+func NewEntryFactory() caskin.EntryFactory {
+	return &factory{}
+}
+
+func (f *factory) NewUser() caskin.User {
+	return &User{}
+}
+
+func (f *factory) NewRole() caskin.Role {
+	return &Role{}
+}
+
+func (f *factory) NewObject() caskin.Object {
+	return &Object{}
+}
+
+func (f *factory) NewDomain() caskin.Domain {
+	return &Domain{}
+}
@@ -2,10 +2,12 @@ package example
 
 import (
 	"errors"
+	"time"
 
 	"github.com/ahmetb/go-linq/v3"
 	"github.com/awatercolorpen/caskin"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type gormMDB struct {
@@ -13,27 +15,27 @@ type gormMDB struct {
 }
 
 func (g *gormMDB) CreateObject(object caskin.Object) error {
-	panic("implement me")
+	return g.db.Create(object).Error
 }
 
 func (g *gormMDB) RecoverObject(object caskin.Object) error {
-	panic("implement me")
+	return recoverDeleted(g.db, object)
 }
 
 func (g *gormMDB) UpdateObject(object caskin.Object) error {
-	panic("implement me")
+	return g.db.Updates(object).Error
 }
 
 func (g *gormMDB) CreateDomain(domain caskin.Domain) error {
-	panic("implement me")
+	return g.db.Create(domain).Error
 }
 
 func (g *gormMDB) RecoverDomain(domain caskin.Domain) error {
-	panic("implement me")
+	return recoverDeleted(g.db, domain)
 }
 
 func (g *gormMDB) UpdateDomain(domain caskin.Domain) error {
-	panic("implement me")
+	return g.db.Updates(domain).Error
 }
 
 func (g *gormMDB) TakeUser(user caskin.User) error {
@@ -52,15 +54,15 @@ func (g *gormMDB) GetUserByID(id []uint64) ([]caskin.User, error) {
 }
 
 func (g *gormMDB) CreateRole(role caskin.Role) error {
-	panic("implement me")
+	return g.db.Create(role).Error
 }
 
 func (g *gormMDB) RecoverRole(role caskin.Role) error {
-	panic("implement me")
+	return recoverDeleted(g.db, role)
 }
 
 func (g *gormMDB) UpdateRole(role caskin.Role) error {
-	panic("implement me")
+	return g.db.Updates(role).Error
 }
 func (g *gormMDB) TakeRole(role caskin.Role) error {
 	return g.db.Where(role).Take(role).Error
@@ -96,6 +98,26 @@ func (g *gormMDB) DeleteRoleByID(id uint64) error {
 	return g.db.Delete(&Role{}, id).Error
 }
 
+func (g *gormMDB) GetDeletedRoleInDomain(domain caskin.Domain, before time.Time) ([]caskin.Role, error) {
+	db, err := deletedBefore(g.db, &Role{}, before)
+	if err != nil {
+		return nil, err
+	}
+
+	var role []*Role
+	if err := db.Where(&Role{DomainID: domain.GetID()}).Find(&role).Error; err != nil {
+		return nil, err
+	}
+
+	var ret []caskin.Role
+	linq.From(role).ToSlice(&ret)
+	return ret, nil
+}
+
+func (g *gormMDB) PurgeRoleByID(id uint64) error {
+	return g.db.Unscoped().Delete(&Role{}, id).Error
+}
+
 func (g *gormMDB) TakeObject(object caskin.Object) error {
 	return g.db.Where(object).Take(object).Error
 }
@@ -135,6 +157,26 @@ func (g *gormMDB) DeleteObjectByID(id uint64) error {
 	return g.db.Delete(&Object{}, id).Error
 }
 
+func (g *gormMDB) GetDeletedObjectInDomain(domain caskin.Domain, before time.Time) ([]caskin.Object, error) {
+	db, err := deletedBefore(g.db, &Object{}, before)
+	if err != nil {
+		return nil, err
+	}
+
+	var object []*Object
+	if err := db.Where(&Object{DomainID: domain.GetID()}).Find(&object).Error; err != nil {
+		return nil, err
+	}
+
+	var ret []caskin.Object
+	linq.From(object).ToSlice(&ret)
+	return ret, nil
+}
+
+func (g *gormMDB) PurgeObjectByID(id uint64) error {
+	return g.db.Unscoped().Delete(&Object{}, id).Error
+}
+
 func (g *gormMDB) TakeDomain(domain caskin.Domain) error {
 	return g.db.Where(domain).Take(domain).Error
 }
@@ -154,6 +196,26 @@ func (g *gormMDB) DeleteDomainByID(id uint64) error {
 	return g.db.Delete(&Domain{}, id).Error
 }
 
+func (g *gormMDB) GetAllDeletedDomain(before time.Time) ([]caskin.Domain, error) {
+	db, err := deletedBefore(g.db, &Domain{}, before)
+	if err != nil {
+		return nil, err
+	}
+
+	var domain []*Domain
+	if err := db.Find(&domain).Error; err != nil {
+		return nil, err
+	}
+
+	var ret []caskin.Domain
+	linq.From(domain).ToSlice(&ret)
+	return ret, nil
+}
+
+func (g *gormMDB) PurgeDomainByID(id uint64) error {
+	return g.db.Unscoped().Delete(&Domain{}, id).Error
+}
+
 func NewGormMDBByDB(db *gorm.DB) caskin.MetaDB {
 	return &gormMDB{
 		db: db,
@@ -182,3 +244,20 @@ func insertOrRecover(db *gorm.DB, item interface{}) error {
 		return tx.Model(item).Update("delete_at", nil).Error
 	})
 }
+
+func recoverDeleted(db *gorm.DB, item interface{}) error {
+	if err := db.Unscoped().Where(item).Take(item).Error; err != nil {
+		return err
+	}
+	return db.Unscoped().Model(item).Update("delete_at", nil).Error
+}
+
+// deletedBefore the records soft deleted before the time
+func deletedBefore(db *gorm.DB, model interface{}, before time.Time) (*gorm.DB, error) {
+	stmt := &gorm.Statement{DB: db}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	column := stmt.Schema.LookUpField("DeletedAt").DBName
+	return db.Unscoped().Where(clause.Lt{Column: column, Value: before}), nil
+}
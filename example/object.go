@@ -10,15 +10,15 @@ import (
 
 // Object sample for caskin.Object interface
 type Object struct {
-	ID        uint64            `gorm:"column:id;primaryKey"                   json:"id,omitempty"`
-	CreatedAt time.Time         `gorm:"column:created_at"                      json:"created_at,omitempty"`
-	UpdatedAt time.Time         `gorm:"column:updated_at"                      json:"updated_at,omitempty"`
-	DeletedAt gorm.DeletedAt    `gorm:"column:delete_at;index"                 json:"-"`
-	Name      string            `gorm:"column:name;index:idx_role,unique"      json:"name,omitempty"`
-	Type      caskin.ObjectType `gorm:"column:type"                            json:"type,omitempty"`
-	Object    string            `gorm:"column:object"                          json:"object,omitempty"`
-	DomainID  uint64            `gorm:"column:tenant_id;index:idx_role,unique" json:"tenant_id,omitempty"`
-	ParentID  uint64            `gorm:"-"                                      json:"parent_id"`
+	ID        uint64            `gorm:"column:id;primaryKey"                     json:"id,omitempty"`
+	CreatedAt time.Time         `gorm:"column:created_at"                        json:"created_at,omitempty"`
+	UpdatedAt time.Time         `gorm:"column:updated_at"                        json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt    `gorm:"column:delete_at;index"                   json:"-"`
+	Name      string            `gorm:"column:name;index:idx_object,unique"      json:"name,omitempty"`
+	Type      caskin.ObjectType `gorm:"column:type"                              json:"type,omitempty"`
+	Object    string            `gorm:"column:object"                            json:"object,omitempty"`
+	DomainID  uint64            `gorm:"column:tenant_id;index:idx_object,unique" json:"tenant_id,omitempty"`
+	ParentID  uint64            `gorm:"-"                                        json:"parent_id"`
 }
 
 const (
@@ -52,6 +52,10 @@ func (o *Object) GetObject() string {
 	return o.Object
 }
 
+func (o *Object) SetObject(object string) {
+	o.Object = object
+}
+
 func (o *Object) GetParentID() uint64 {
 	return o.ParentID
 }
@@ -60,6 +64,10 @@ func (o *Object) SetParentID(pid uint64) {
 	o.ParentID = pid
 }
 
+func (o *Object) GetDomainID() uint64 {
+	return o.DomainID
+}
+
 func (o *Object) SetDomainID(did uint64) {
 	o.DomainID = did
 }
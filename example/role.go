@@ -44,6 +44,10 @@ func (r *Role) GetObject() string {
 	return r.Object
 }
 
+func (r *Role) SetObject(object string) {
+	r.Object = object
+}
+
 func (r *Role) GetParentID() uint64 {
 	return r.ParentID
 }
@@ -52,6 +56,10 @@ func (r *Role) SetParentID(pid uint64) {
 	r.ParentID = pid
 }
 
+func (r *Role) GetDomainID() uint64 {
+	return r.DomainID
+}
+
 func (r *Role) SetDomainID(did uint64) {
 	r.DomainID = did
 }
@@ -28,13 +28,7 @@ func (e *executor) check(action Action, one entry) error {
 	}
 
 	if ok := Check(e.e, u, d, action, e.factory.NewObject, one); !ok {
-		switch action {
-		case Read:
-			return ErrNoReadPermission
-		case Write:
-			return ErrNoWritePermission
-		default:
-		}
+		return &PermissionError{User: u, Domain: d, Entry: one.Encode(), Action: action}
 	}
 
 	return nil
@@ -47,7 +41,7 @@ func (e *executor) checkParentEntryWrite(one parentEntry, take takeParentEntry)
 	}
 
 	if ok := Check(e.e, u, d, Write, e.factory.NewObject, one); !ok {
-		return ErrNoWritePermission
+		return &PermissionError{User: u, Domain: d, Entry: one.Encode(), Action: Write}
 	}
 
 	for _, v := range []uint64{
@@ -64,11 +58,43 @@ func (e *executor) checkParentEntryWrite(one parentEntry, take takeParentEntry)
 		}
 
 		if ok := Check(e.e, u, d, Write, e.factory.NewObject, toCheck); !ok {
-			return ErrNoWritePermission
+			return &PermissionError{User: u, Domain: d, Entry: toCheck.Encode(), Action: Write}
 		}
 	}
 
 	return nil
 }
 
+func (e *executor) checkSuperadmin() error {
+	u, _, err := e.provider.Get()
+	if err != nil {
+		return err
+	}
+
+	if ok, err := e.e.IsSuperAdmin(u); err != nil || !ok {
+		return ErrIsNotSuperAdmin
+	}
+
+	return nil
+}
+
+// checkDomain current user is superadmin, or the domain is current domain,
+// because domain is not an object whose permission could be checked
+func (e *executor) checkDomain(domain Domain) error {
+	_, currentDomain, err := e.provider.Get()
+	if err != nil {
+		return err
+	}
+
+	if currentDomain != nil && currentDomain.GetID() == domain.GetID() {
+		return nil
+	}
+
+	if err := e.checkSuperadmin(); err != nil {
+		return ErrCrossDomain
+	}
+
+	return nil
+}
+
 type takeParentEntry func(uint64) (parentEntry, error)
@@ -0,0 +1,150 @@
+package caskin
+
+// CompareUserAccess if current user has user a and b's read permission
+// 1. get objects which current user has read permission in current domain
+// 2. enforce a and b on these objects with read, write and the actions of current domain's policies
+// 3. compare a and b's effective accesses
+func (e *executor) CompareUserAccess(a, b User) (*AccessComparison, error) {
+	for _, v := range []User{a, b} {
+		if err := e.takeUserToRead(v); err != nil {
+			return nil, err
+		}
+	}
+
+	currentUser, currentDomain, err := e.provider.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := e.mdb.GetObjectInDomain(currentDomain)
+	if err != nil {
+		return nil, err
+	}
+	objects = e.filterWithNoError(currentUser, currentDomain, Read, objects).([]Object)
+
+	actions := []Action{Read, Write}
+	seen := map[Action]bool{Read: true, Write: true}
+	for _, p := range e.e.GetPoliciesInDomain(currentDomain) {
+		if !seen[p.Action] {
+			seen[p.Action] = true
+			actions = append(actions, p.Action)
+		}
+	}
+
+	var accesses [2][]*Access
+	for i, u := range []User{a, b} {
+		for _, o := range objects {
+			for _, action := range actions {
+				ok, err := e.e.Enforce(u, o, currentDomain, action)
+				if err != nil {
+					return nil, err
+				}
+				if ok {
+					accesses[i] = append(accesses[i], &Access{Object: o, Action: action})
+				}
+			}
+		}
+	}
+
+	return compareAccess(accesses[0], accesses[1]), nil
+}
+
+// CompareRoleAccess if current user has role a and b's read permission
+// 1. get a and b's policies in current domain on objects which current user has read permission
+// 2. compare a and b's policies
+func (e *executor) CompareRoleAccess(a, b Role) (*AccessComparison, error) {
+	currentUser, currentDomain, err := e.provider.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range []Role{a, b} {
+		if err := isValid(v); err != nil {
+			return nil, err
+		}
+		if err := e.mdb.TakeRole(v); err != nil {
+			return nil, &NotFoundError{Kind: RoleEntry, ID: v.GetID()}
+		}
+		if err := e.check(Read, v); err != nil {
+			return nil, err
+		}
+		if err := isInDomain(v, currentDomain); err != nil {
+			return nil, err
+		}
+	}
+
+	objects, err := e.mdb.GetObjectInDomain(currentDomain)
+	if err != nil {
+		return nil, err
+	}
+	objects = e.filterWithNoError(currentUser, currentDomain, Read, objects).([]Object)
+	om := getIDMap(objects)
+
+	var accesses [2][]*Access
+	for i, r := range []Role{a, b} {
+		for _, p := range e.e.GetPoliciesForRoleInDomain(r, currentDomain) {
+			if o, ok := om[p.Object.GetID()]; ok {
+				accesses[i] = append(accesses[i], &Access{Object: o.(Object), Action: p.Action})
+			}
+		}
+	}
+
+	return compareAccess(accesses[0], accesses[1]), nil
+}
+
+// CopyAccess if current user has user a's write permission, user b's read permission
+// and write permission of all b's roles in current domain
+// 1. b's roles must be in current domain
+// 2. add a's g to b's roles which a doesn't have in current domain, a keeps its own roles
+func (e *executor) CopyAccess(a, b User) error {
+	for _, v := range []User{a, b} {
+		if err := e.takeUserToRead(v); err != nil {
+			return err
+		}
+	}
+	if err := e.check(Write, a); err != nil {
+		return err
+	}
+
+	_, currentDomain, err := e.provider.Get()
+	if err != nil {
+		return err
+	}
+
+	roles, err := e.mdb.GetRoleByID(getIDList(e.e.GetRolesForUserInDomain(b, currentDomain)))
+	if err != nil {
+		return err
+	}
+	for _, v := range roles {
+		if err := isInDomain(v, currentDomain); err != nil {
+			return err
+		}
+		if err := e.check(Write, v); err != nil {
+			return err
+		}
+	}
+
+	has := getIDMap(e.e.GetRolesForUserInDomain(a, currentDomain))
+	for _, v := range roles {
+		if _, ok := has[v.GetID()]; ok {
+			continue
+		}
+		if err := e.e.AddRoleForUserInDomain(a, v, currentDomain); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (e *executor) takeUserToRead(user User) error {
+	if err := isValid(user); err != nil {
+		return err
+	}
+
+	if err := e.mdb.TakeUser(user); err != nil {
+		return &NotFoundError{Kind: UserEntry, ID: user.GetID()}
+	}
+
+	return e.check(Read, user)
+}
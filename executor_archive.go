@@ -0,0 +1,258 @@
+package caskin
+
+// ExportDomain if there exist the domain, and it is current domain or current user is superadmin
+// 1. get the domain's roles, objects, role's tree, object's tree and policies
+// 2. get the domain's domain admin role's g and user to roles 's g
+// 3. keep only the roles and objects which current user has read permission in the domain
+func (e *executor) ExportDomain(domain Domain) (*DomainArchive, error) {
+	if err := isValid(domain); err != nil {
+		return nil, err
+	}
+
+	if err := e.mdb.TakeDomain(domain); err != nil {
+		return nil, &NotFoundError{Kind: DomainEntry, ID: domain.GetID()}
+	}
+
+	if err := e.checkDomain(domain); err != nil {
+		return nil, err
+	}
+
+	return e.exportReadableDomain(domain)
+}
+
+// ImportDomain if there exist the domain, and it is current domain or current user is superadmin
+// 1. the archive's roles and objects must be in the archive's domain if it has one
+// 2. current user must have write permission of every role and object to create, and the roles assigned to users are ones of them
+// 3. only superadmin could import the archive's domain admin roles
+// 4. create objects and roles of the archive with new id in the domain, it fails if they conflict with the existing ones
+// 5. remap the archive's id to new id, and add g, g2, p into casbin
+// 6. add user to roles 's g for users existing in metadata database
+func (e *executor) ImportDomain(archive *DomainArchive, domain Domain) (*DomainImport, error) {
+	if archive == nil || archive.DomainSpec == nil {
+		return nil, ErrNil
+	}
+
+	if err := archive.validate(); err != nil {
+		return nil, err
+	}
+
+	if archive.Domain != nil {
+		if err := archive.checkInDomain(archive.Domain); err != nil {
+			return nil, err
+		}
+	}
+
+	var result *DomainImport
+	fn := func(domain Domain) error {
+		if err := e.checkDomain(domain); err != nil {
+			return err
+		}
+		if err := e.checkDomainImport(archive, domain); err != nil {
+			return err
+		}
+		var err error
+		result, err = e.importDomain(archive, domain)
+		return err
+	}
+
+	err := e.writeDomain(domain, fn)
+	return result, err
+}
+
+// checkDomainImport check current user could import the archive into the domain
+// 1. the archive's domain admin roles need superadmin
+// 2. every role and object to create need write permission by its object code remapped to a new object,
+// so it is only passed by the superadmin or domain admin who has permission of the objects not existing yet
+func (e *executor) checkDomainImport(archive *DomainArchive, domain Domain) error {
+	if len(archive.DomainAdminRoles) != 0 {
+		if err := e.checkSuperadmin(); err != nil {
+			return err
+		}
+	}
+
+	newObject := e.factory.NewObject()
+	newObject.SetDomainID(domain.GetID())
+	codes := map[string]string{}
+	for _, v := range archive.Objects {
+		codes[v.Encode()] = newObject.Encode()
+	}
+
+	for _, v := range archive.Objects {
+		o := e.factory.NewObject()
+		if err := copyEntry(v, o, codes); err != nil {
+			return err
+		}
+		if err := e.check(Write, o); err != nil {
+			return err
+		}
+	}
+	for _, v := range archive.Roles {
+		r := e.factory.NewRole()
+		if err := copyEntry(v, r, codes); err != nil {
+			return err
+		}
+		if err := e.check(Write, r); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// exportReadableDomain get the domain's archive pruned to the roles and objects
+// which current user has read permission in the domain
+func (e *executor) exportReadableDomain(domain Domain) (*DomainArchive, error) {
+	archive, err := e.exportDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	user, _, err := e.provider.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	roles := e.filterWithNoError(user, domain, Read, archive.Roles).([]Role)
+	objects := e.filterWithNoError(user, domain, Read, archive.Objects).([]Object)
+	archive.prune(roles, objects)
+	return archive, nil
+}
+
+func (e *executor) exportDomain(domain Domain) (*DomainArchive, error) {
+	spec, err := e.getDomainSpec(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := &DomainArchive{
+		Domain:           domain,
+		DomainSpec:       spec,
+		DomainAdminRoles: e.e.GetDomainAdminRolesInDomain(domain),
+	}
+
+	um := map[uint64]*RolesForUser{}
+	var uid []uint64
+	for _, role := range spec.Roles {
+		for _, user := range e.e.GetUsersForRoleInDomain(role, domain) {
+			ru, ok := um[user.GetID()]
+			if !ok {
+				ru = &RolesForUser{User: user}
+				um[user.GetID()] = ru
+				uid = append(uid, user.GetID())
+			}
+			ru.Roles = append(ru.Roles, role)
+		}
+	}
+	for _, v := range uid {
+		archive.RolesForUsers = append(archive.RolesForUsers, um[v])
+	}
+
+	return archive, nil
+}
+
+func (e *executor) importDomain(archive *DomainArchive, domain Domain) (*DomainImport, error) {
+	result := &DomainImport{
+		RoleID:   map[uint64]uint64{},
+		ObjectID: map[uint64]uint64{},
+	}
+
+	// create objects without object code, then update it by the new object code
+	objects := map[uint64]Object{}
+	for _, v := range archive.Objects {
+		o := e.factory.NewObject()
+		if err := copyEntry(v, o, map[string]string{v.GetObject(): ""}); err != nil {
+			return nil, err
+		}
+		o.SetID(0)
+		o.SetParentID(0)
+		o.SetDomainID(domain.GetID())
+		if err := e.mdb.CreateObject(o); err != nil {
+			return nil, err
+		}
+		objects[v.GetID()] = o
+		result.ObjectID[v.GetID()] = o.GetID()
+	}
+
+	codes := map[string]string{}
+	for k, v := range objects {
+		o := e.factory.NewObject()
+		o.SetID(k)
+		codes[o.Encode()] = v.Encode()
+	}
+
+	for _, v := range archive.Objects {
+		o := objects[v.GetID()]
+		if err := copyEntry(v, o, codes); err != nil {
+			return nil, err
+		}
+		o.SetID(result.ObjectID[v.GetID()])
+		o.SetParentID(0)
+		o.SetDomainID(domain.GetID())
+		if err := e.mdb.UpdateObject(o); err != nil {
+			return nil, err
+		}
+	}
+
+	roles := map[uint64]Role{}
+	for _, v := range archive.Roles {
+		r := e.factory.NewRole()
+		if err := copyEntry(v, r, codes); err != nil {
+			return nil, err
+		}
+		r.SetID(0)
+		r.SetParentID(0)
+		r.SetDomainID(domain.GetID())
+		if err := e.mdb.CreateRole(r); err != nil {
+			return nil, err
+		}
+		roles[v.GetID()] = r
+		result.RoleID[v.GetID()] = r.GetID()
+	}
+
+	// restore g, g2, p by new id
+	for _, v := range archive.Roles {
+		if p, ok := roles[v.GetParentID()]; ok {
+			if err := e.e.AddParentForRoleInDomain(roles[v.GetID()], p, domain); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, v := range archive.Objects {
+		if p, ok := objects[v.GetParentID()]; ok {
+			if err := e.e.AddParentForObjectInDomain(objects[v.GetID()], p, domain); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, v := range archive.Policies {
+		r, o := roles[v.Role.GetID()], objects[v.Object.GetID()]
+		if err := e.e.AddPolicyInDomain(r, o, domain, v.Action); err != nil {
+			return nil, err
+		}
+	}
+	for _, v := range archive.DomainAdminRoles {
+		if r, ok := roles[v.GetID()]; ok {
+			if err := e.e.AddDomainAdminRoleInDomain(r, domain); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, v := range archive.RolesForUsers {
+		user := e.factory.NewUser()
+		user.SetID(v.User.GetID())
+		if err := e.mdb.TakeUser(user); err != nil {
+			result.MissingUsers = append(result.MissingUsers, user.GetID())
+			continue
+		}
+		for _, role := range v.Roles {
+			if r, ok := roles[role.GetID()]; ok {
+				if err := e.e.AddRoleForUserInDomain(user, r, domain); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,138 @@
+package caskin
+
+import "time"
+
+// VerifyConsistency if current user is superadmin
+// 1. get all domains with their roles and objects, and users referred by g from metadata database
+// 2. the soft deleted domains, roles and objects are got too if metadata database is TrashMetaDB
+// 3. decode every p, g, g2 rule and check the entries it refers to
+// 4. report rules which refer to not exists entries as orphan
+// 5. report rules which refer to entries of other domain or undecodable code as dangling
+// 6. report rules which refer to soft deleted entries as soft deleted, they are kept to recover the entries
+func (e *executor) VerifyConsistency() (*ConsistencyReport, error) {
+	if err := e.checkSuperadmin(); err != nil {
+		return nil, err
+	}
+
+	return e.verifyConsistency()
+}
+
+// RepairConsistency if current user is superadmin and metadata database is TrashMetaDB
+// 1. verify consistency the same as VerifyConsistency, the rules of soft deleted entries are not orphan
+// 2. remove the orphan and dangling rules from casbin by p, g, g2 in turn
+// 3. remove the soft deleted rules too only if it is asked, then the soft deleted entries can't be recovered with their rules
+// 4. if it fails partway, return the report with the error, the removed rules' issues are marked as removed
+func (e *executor) RepairConsistency(softDeleted bool) (*ConsistencyReport, error) {
+	if _, err := e.trash(); err != nil {
+		return nil, err
+	}
+
+	if err := e.checkSuperadmin(); err != nil {
+		return nil, err
+	}
+
+	report, err := e.verifyConsistency()
+	if err != nil {
+		return nil, err
+	}
+
+	if report.IsEmpty() {
+		return report, nil
+	}
+
+	for _, ptype := range []string{PolicyPType, RolePType, ObjectPType} {
+		issues := report.issuesOf(ptype, softDeleted)
+		var rules []*Rule
+		for _, v := range issues {
+			rules = append(rules, v.Rule)
+		}
+		if len(rules) == 0 {
+			continue
+		}
+		if err := e.e.RemoveRules(rules); err != nil {
+			return report, err
+		}
+		for _, v := range issues {
+			v.Removed = true
+		}
+	}
+	report.Repaired = true
+
+	return report, nil
+}
+
+func (e *executor) verifyConsistency() (*ConsistencyReport, error) {
+	checker := newConsistencyChecker(e.factory)
+	now := time.Now()
+	domains, err := e.mdb.GetAllDomain()
+	if err != nil {
+		return nil, err
+	}
+	trash, trashErr := e.trash()
+	if trashErr == nil {
+		deletedDomains, err := trash.GetAllDeletedDomain(now)
+		if err != nil {
+			return nil, err
+		}
+		for _, v := range deletedDomains {
+			checker.addDeleted(v)
+		}
+		domains = append(domains, deletedDomains...)
+	}
+	for _, v := range domains {
+		roles, err := e.mdb.GetRoleInDomain(v)
+		if err != nil {
+			return nil, err
+		}
+		objects, err := e.mdb.GetObjectInDomain(v)
+		if err != nil {
+			return nil, err
+		}
+		checker.addDomain(v, roles, objects)
+		if trashErr != nil {
+			continue
+		}
+
+		deletedRoles, err := trash.GetDeletedRoleInDomain(v, now)
+		if err != nil {
+			return nil, err
+		}
+		deletedObjects, err := trash.GetDeletedObjectInDomain(v, now)
+		if err != nil {
+			return nil, err
+		}
+		checker.addDomain(v, deletedRoles, deletedObjects)
+		for _, r := range deletedRoles {
+			checker.addDeleted(r)
+		}
+		for _, o := range deletedObjects {
+			checker.addDeleted(o)
+		}
+	}
+
+	rules := e.e.GetAllRules()
+	var uid []uint64
+	for _, v := range rules {
+		if v.PType != RolePType || len(v.Values) == 0 {
+			continue
+		}
+		user := e.factory.NewUser()
+		if err := user.Decode(v.Values[0]); err == nil {
+			uid = append(uid, user.GetID())
+		}
+	}
+	users, err := e.mdb.GetUserByID(uid)
+	if err != nil {
+		return nil, err
+	}
+	checker.addUsers(users)
+
+	report := &ConsistencyReport{Checked: len(rules)}
+	for _, v := range rules {
+		if issue := checker.check(v); issue != nil {
+			report.Issues = append(report.Issues, issue)
+		}
+	}
+
+	return report, nil
+}
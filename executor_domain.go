@@ -1,5 +1,7 @@
 package caskin
 
+import "fmt"
+
 // CreateDomain if there does not exist the domain, then create a new one
 // 1. create a new domain into metadata database
 // 2. initialize the new domain
@@ -10,20 +12,73 @@ func (e *executor) CreateDomain(domain Domain) error {
 // RecoverDomain if there exist the domain but soft deleted, then recover it
 // 1. recover the soft delete one domain at metadata database
 // 2. re initialize the recovering domain
+// 3. restore the domain's tombstone rules if it is restore on recover and current user is superadmin
 func (e *executor) RecoverDomain(domain Domain) error {
-	return e.createOrRecoverDomain(domain, e.mdb.RecoverDomain)
+	if e.option.IsRestoreOnRecover() {
+		if err := e.checkRestore(domain); err != nil {
+			return err
+		}
+	}
+
+	if err := e.createOrRecoverDomain(domain, e.mdb.RecoverDomain); err != nil {
+		return err
+	}
+
+	return e.restoreRules(domain)
+}
+
+// CloneDomain if there exist the source domain but not the target domain,
+// and the source is current domain or current user is superadmin
+// 1. get source domain's archive with roles and objects which user has read permission in the source, they must be in the source
+// 2. create the target domain into metadata database without initializing
+// 3. import the archive into target domain with new id, user to roles 's g is optional
+// 4. roll back the target domain if it fails to import
+func (e *executor) CloneDomain(source Domain, target Domain, withUser bool) error {
+	archive, err := e.ExportDomain(source)
+	if err != nil {
+		return err
+	}
+
+	if err := archive.checkInDomain(source); err != nil {
+		return err
+	}
+
+	if err := e.mdb.TakeDomain(target); err == nil {
+		return &ConflictError{Kind: DomainEntry, ID: target.GetID()}
+	}
+
+	if !withUser {
+		archive.RolesForUsers = nil
+	}
+
+	if err := e.mdb.CreateDomain(target); err != nil {
+		return err
+	}
+
+	if _, err := e.importDomain(archive, target); err != nil {
+		if rerr := e.rollbackDomain(target); rerr != nil {
+			return fmt.Errorf("%w, and failed to roll back domain %v: %v", err, target.GetID(), rerr)
+		}
+		return err
+	}
+
+	return nil
 }
 
 // DeleteDomain if user has domain's write permission
-// 1. delete all user's g in the domain
-// 2. don't delete any role's g or object's g2 in the domain
-// 3. soft delete one domain in metadata database
-func (e *executor) DeleteDomain(domain Domain) error {
+// 1. deal with the domain's users, roles and objects by the DeleteOption
+// 2. delete all user's g in the domain
+// 3. archive the deleted g as the domain's tombstone if there is TombstoneStore
+// 4. don't delete any role's g or object's g2 in the domain unless it is DeleteCascade
+// 5. soft delete one domain in metadata database
+func (e *executor) DeleteDomain(domain Domain, option ...DeleteOption) error {
+	deleteOption, err := getDeleteOption(option)
+	if err != nil {
+		return err
+	}
+
 	fn := func(domain Domain) error {
-		if err := e.e.RemoveUsersInDomain(domain); err != nil {
-			return err
-		}
-		return e.mdb.DeleteDomainByID(domain.GetID())
+		return e.deleteDomain(domain, deleteOption)
 	}
 
 	return e.writeDomain(domain, fn)
@@ -59,7 +114,7 @@ func (e *executor) GetAllDomain() ([]Domain, error) {
 
 func (e *executor) createOrRecoverDomain(domain Domain, fn func(Domain) error) error {
 	if err := e.mdb.TakeDomain(domain); err == nil {
-		return ErrAlreadyExists
+		return &ConflictError{Kind: DomainEntry, ID: domain.GetID()}
 	}
 
 	if err := fn(domain); err != nil {
@@ -75,7 +130,7 @@ func (e *executor) writeDomain(domain Domain, fn func(Domain) error) error {
 	}
 
 	if err := e.mdb.TakeDomain(domain); err != nil {
-		return ErrNotExists
+		return &NotFoundError{Kind: DomainEntry, ID: domain.GetID()}
 	}
 
 	if err := e.check(Write, domain); err != nil {
@@ -85,12 +140,46 @@ func (e *executor) writeDomain(domain Domain, fn func(Domain) error) error {
 	return fn(domain)
 }
 
+// rollbackDomain remove a new domain which fails to be filled
+// 1. remove the domain's g, g2 and p
+// 2. delete the domain's roles, objects and the domain in metadata database
+func (e *executor) rollbackDomain(domain Domain) error {
+	if err := e.e.RemoveRules(e.e.GetRulesInDomain(domain)); err != nil {
+		return err
+	}
+
+	roles, err := e.mdb.GetRoleInDomain(domain)
+	if err != nil {
+		return err
+	}
+	for _, v := range roles {
+		if err := e.mdb.DeleteRoleByID(v.GetID()); err != nil {
+			return err
+		}
+	}
+
+	objects, err := e.mdb.GetObjectInDomain(domain)
+	if err != nil {
+		return err
+	}
+	for _, v := range objects {
+		if err := e.mdb.DeleteObjectByID(v.GetID()); err != nil {
+			return err
+		}
+	}
+
+	return e.mdb.DeleteDomainByID(domain.GetID())
+}
+
 // initializeDomain it is reentrant to initialize a new domain
-// 1. get roles, objects, policies form DomainCreator
+// 1. get roles, objects, policies form the latest DomainTemplate
 // 2. upsert roles, objects into metadata database
 // 3. add policies as p into casbin
+// 4. add domain admin role's g into casbin if domain admin is enabled
+// 5. update the domain's template version if it is VersionedDomain
 func (e *executor) initializeDomain(domain Domain) error {
-	roles, objects, policies := e.option.DomainCreator(domain)
+	template := e.option.GetLatestDomainTemplate()
+	roles, objects, policies := template.Creator(domain)
 	for _, v := range roles {
 		if err := e.mdb.UpsertRole(v); err != nil {
 			return err
@@ -109,5 +198,92 @@ func (e *executor) initializeDomain(domain Domain) error {
 		}
 	}
 
-	return nil
-}
\ No newline at end of file
+	if role := e.option.GetDomainAdminRole(roles); role != nil {
+		if err := e.e.AddDomainAdminRoleInDomain(role, domain); err != nil {
+			return err
+		}
+	}
+
+	return e.updateDomainTemplateVersion(domain, template.Version)
+}
+
+func (e *executor) updateDomainTemplateVersion(domain Domain, version uint64) error {
+	d, ok := domain.(VersionedDomain)
+	if !ok || d.GetTemplateVersion() == version {
+		return nil
+	}
+
+	d.SetTemplateVersion(version)
+	return e.mdb.UpdateDomain(d)
+}
+
+// deleteDomain delete the domain
+// 1. DeleteRestrict: fail if the domain has users, roles or objects
+// 2. DeleteCascade: soft delete all roles and objects of the domain with their g, g2 and p, if current user has all of their write permission
+// 3. DeleteDetach: fail with ErrInvalidDeleteOption, there is no parent of domain
+func (e *executor) deleteDomain(domain Domain, option DeleteOption) error {
+	if option == DeleteDetach {
+		return fmt.Errorf("%w: %v of domain", ErrInvalidDeleteOption, option)
+	}
+
+	var roles []Role
+	var objects []Object
+	if option == DeleteRestrict || option == DeleteCascade {
+		var err error
+		if roles, err = e.mdb.GetRoleInDomain(domain); err != nil {
+			return err
+		}
+		if objects, err = e.mdb.GetObjectInDomain(domain); err != nil {
+			return err
+		}
+	}
+
+	switch option {
+	case DeleteRestrict:
+		dependents := &DependentsError{Code: domain.Encode(), Roles: roles, Objects: objects}
+		for _, v := range getIDMap(e.e.GetUsersInDomain(domain)) {
+			dependents.Users = append(dependents.Users, v.(User))
+		}
+		if !dependents.isEmpty() {
+			return dependents
+		}
+	case DeleteCascade:
+		// check all of them before anything is deleted
+		for _, v := range roles {
+			if err := e.check(Write, v); err != nil {
+				return err
+			}
+		}
+		for _, v := range objects {
+			if err := e.check(Write, v); err != nil {
+				return err
+			}
+		}
+	}
+
+	rules := userRules(e.e.GetRulesInDomain(domain), e.factory)
+	if err := e.removeRules(domain, rules); err != nil {
+		return err
+	}
+
+	// roles and objects are empty here unless it is DeleteCascade
+	for _, v := range roles {
+		if err := e.removeRules(v, roleRules(e.e.GetRulesInDomain(domain), v)); err != nil {
+			return err
+		}
+		if err := e.mdb.DeleteRoleByID(v.GetID()); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range objects {
+		if err := e.removeRules(v, objectRules(e.e.GetRulesInDomain(domain), v)); err != nil {
+			return err
+		}
+		if err := e.mdb.DeleteObjectByID(v.GetID()); err != nil {
+			return err
+		}
+	}
+
+	return e.mdb.DeleteDomainByID(domain.GetID())
+}
@@ -0,0 +1,99 @@
+package caskin
+
+// AddDomainAdminUser if current user has user and domain admin role's write permission
+// 1. add the user as domain admin role in current domain
+func (e *executor) AddDomainAdminUser(user User) error {
+	return e.writeDomainAdminUser(user, e.e.AddRoleForUserInDomain)
+}
+
+// DeleteDomainAdminUser if current user has user and domain admin role's write permission
+// 1. delete the user from domain admin role in current domain
+func (e *executor) DeleteDomainAdminUser(user User) error {
+	return e.writeDomainAdminUser(user, e.e.RemoveRoleForUserInDomain)
+}
+
+// GetAllDomainAdminUser if current user has user's read permission
+// 1. get all domain admin user in current domain
+func (e *executor) GetAllDomainAdminUser() ([]User, error) {
+	if !e.option.IsEnableDomainAdmin() {
+		return nil, ErrDomainAdminIsNoEnabled
+	}
+
+	_, currentDomain, err := e.provider.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	var us []User
+	for _, v := range e.e.GetDomainAdminRolesInDomain(currentDomain) {
+		us = append(us, e.e.GetUsersForRoleInDomain(v, currentDomain)...)
+	}
+	id := getIDList(us)
+	users, err := e.mdb.GetUserByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := e.filter(Read, users)
+	if err != nil {
+		return nil, err
+	}
+
+	return out.([]User), nil
+}
+
+func (e *executor) writeDomainAdminUser(user User, fn func(User, Role, Domain) error) error {
+	if !e.option.IsEnableDomainAdmin() {
+		return ErrDomainAdminIsNoEnabled
+	}
+
+	if err := isValid(user); err != nil {
+		return err
+	}
+
+	if err := e.mdb.TakeUser(user); err != nil {
+		return &NotFoundError{Kind: UserEntry, ID: user.GetID()}
+	}
+
+	if err := e.check(Write, user); err != nil {
+		return err
+	}
+
+	role, err := e.takeDomainAdminRole()
+	if err != nil {
+		return err
+	}
+
+	if err := e.check(Write, role); err != nil {
+		return err
+	}
+
+	_, currentDomain, err := e.provider.Get()
+	if err != nil {
+		return err
+	}
+
+	return fn(user, role, currentDomain)
+}
+
+func (e *executor) takeDomainAdminRole() (Role, error) {
+	_, currentDomain, err := e.provider.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	rs := e.e.GetDomainAdminRolesInDomain(currentDomain)
+	if len(rs) == 0 {
+		return nil, ErrDomainAdminRoleNotExists
+	}
+
+	roles, err := e.mdb.GetRoleByID(getIDList(rs))
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) == 0 {
+		return nil, ErrDomainAdminRoleNotExists
+	}
+
+	return roles[0], nil
+}
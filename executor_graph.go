@@ -0,0 +1,53 @@
+package caskin
+
+// ExportGraph if there exist the domain, and it is current domain or current user is superadmin
+// 1. get the domain's archive of the roles and objects which current user has read permission
+// 2. the option's object must be in the domain and current user must have its read permission
+// 3. build the graph of users, role's tree, object's tree and policies filtered by the option
+func (e *executor) ExportGraph(domain Domain, option *GraphOption) (*Graph, error) {
+	if err := isValid(domain); err != nil {
+		return nil, err
+	}
+
+	if err := e.mdb.TakeDomain(domain); err != nil {
+		return nil, &NotFoundError{Kind: DomainEntry, ID: domain.GetID()}
+	}
+
+	if err := e.checkDomain(domain); err != nil {
+		return nil, err
+	}
+
+	archive, err := e.exportReadableDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	var uid []uint64
+	for _, v := range archive.RolesForUsers {
+		uid = append(uid, v.User.GetID())
+	}
+	if option != nil && option.User != nil {
+		if err := e.mdb.TakeUser(option.User); err != nil {
+			return nil, &NotFoundError{Kind: UserEntry, ID: option.User.GetID()}
+		}
+		uid = append(uid, option.User.GetID())
+	}
+	if option != nil && option.Object != nil {
+		if err := e.mdb.TakeObject(option.Object); err != nil {
+			return nil, &NotFoundError{Kind: ObjectEntry, ID: option.Object.GetID()}
+		}
+		if err := isInDomain(option.Object, domain); err != nil {
+			return nil, err
+		}
+		if err := e.check(Read, option.Object); err != nil {
+			return nil, err
+		}
+	}
+
+	users, err := e.mdb.GetUserByID(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	return newGraph(archive, users, option), nil
+}
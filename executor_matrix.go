@@ -0,0 +1,26 @@
+package caskin
+
+// GetPermissionMatrix if there exist the domain, and it is current domain or current user is superadmin
+// 1. get the domain's roles, objects, role's tree, object's tree and policies
+// 2. keep only the roles and objects which current user has read permission in the domain
+// 3. build the matrix of the roles' actions on the objects
+func (e *executor) GetPermissionMatrix(domain Domain) (*PermissionMatrix, error) {
+	if err := isValid(domain); err != nil {
+		return nil, err
+	}
+
+	if err := e.mdb.TakeDomain(domain); err != nil {
+		return nil, &NotFoundError{Kind: DomainEntry, ID: domain.GetID()}
+	}
+
+	if err := e.checkDomain(domain); err != nil {
+		return nil, err
+	}
+
+	archive, err := e.exportReadableDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPermissionMatrix(archive.DomainSpec, domain), nil
+}
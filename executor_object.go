@@ -1 +1,119 @@
-package caskin
\ No newline at end of file
+package caskin
+
+// DeleteObject if there exist the object and current user has object's write permission
+// 1. deal with the object's policies and child objects by the DeleteOption
+// 2. delete the object's g2 as parent or child, and the object's p in current domain
+// 3. archive the deleted g2 and p as the object's tombstone if there is TombstoneStore
+// 4. soft delete one object in metadata database
+func (e *executor) DeleteObject(object Object, option ...DeleteOption) error {
+	deleteOption, err := getDeleteOption(option)
+	if err != nil {
+		return err
+	}
+
+	fn := func(object Object) error {
+		_, domain, err := e.provider.Get()
+		if err != nil {
+			return err
+		}
+		return e.deleteObject(object, domain, deleteOption)
+	}
+
+	return e.writeObject(object, fn)
+}
+
+func (e *executor) writeObject(object Object, fn func(Object) error) error {
+	if err := isValid(object); err != nil {
+		return err
+	}
+
+	if err := e.mdb.TakeObject(object); err != nil {
+		return &NotFoundError{Kind: ObjectEntry, ID: object.GetID()}
+	}
+
+	_, domain, err := e.provider.Get()
+	if err != nil {
+		return err
+	}
+
+	if err := isInDomain(object, domain); err != nil {
+		return err
+	}
+
+	take := func(id uint64) (parentEntry, error) {
+		o := e.factory.NewObject()
+		o.SetID(id)
+		o.SetDomainID(domain.GetID())
+		err := e.mdb.TakeObject(o)
+		return o, err
+	}
+
+	if err := e.checkParentEntryWrite(object, take); err != nil {
+		return err
+	}
+
+	return fn(object)
+}
+
+// deleteObject delete the object in the domain
+// 1. DeleteRestrict: fail if the object has policies or child objects
+// 2. DeleteCascade: delete all descendant objects if current user has all of their write permission
+// 3. DeleteDetach: re parent child objects to the object's parent
+func (e *executor) deleteObject(object Object, domain Domain, option DeleteOption) error {
+	tree := getTree(e.e.GetObjectsInDomain(domain))
+	var children []Object
+	if id := getChildrenID(tree, object.GetID()); len(id) != 0 {
+		var err error
+		if children, err = e.mdb.GetObjectByID(id); err != nil {
+			return err
+		}
+	}
+
+	switch option {
+	case DeleteRestrict:
+		dependents := &DependentsError{Code: object.Encode(), Objects: children}
+		for _, v := range e.e.GetPoliciesInDomain(domain) {
+			if v.Object.GetID() == object.GetID() {
+				dependents.Policies = append(dependents.Policies, v)
+			}
+		}
+		if !dependents.isEmpty() {
+			return dependents
+		}
+	case DeleteCascade:
+		descendants, err := e.mdb.GetObjectByID(getDescendantsID(tree, object.GetID())[1:])
+		if err != nil {
+			return err
+		}
+		// check all of them before anything is deleted
+		for _, v := range descendants {
+			if err := e.check(Write, v); err != nil {
+				return err
+			}
+		}
+		for _, v := range descendants {
+			if err := e.removeRules(v, objectRules(e.e.GetRulesInDomain(domain), v)); err != nil {
+				return err
+			}
+			if err := e.mdb.DeleteObjectByID(v.GetID()); err != nil {
+				return err
+			}
+		}
+	case DeleteDetach:
+		if parentID, ok := tree[object.GetID()]; ok {
+			parent := e.factory.NewObject()
+			parent.SetID(parentID)
+			for _, v := range children {
+				if err := e.e.AddParentForObjectInDomain(v, parent, domain); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	rules := objectRules(e.e.GetRulesInDomain(domain), object)
+	if err := e.removeRules(object, rules); err != nil {
+		return err
+	}
+	return e.mdb.DeleteObjectByID(object.GetID())
+}
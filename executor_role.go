@@ -1,6 +1,10 @@
 package caskin
 
-import "github.com/ahmetb/go-linq/v3"
+import (
+	"time"
+
+	"github.com/ahmetb/go-linq/v3"
+)
 
 // GetAllUsersForRole
 // 1. get all user which current user has read permission in current domain
@@ -58,7 +62,7 @@ func (e *executor) ModifyUsersForRole(ur *UsersForRole) error {
 	}
 
 	if err := e.mdb.TakeRole(ur.Role); err != nil {
-		return ErrNotExists
+		return &NotFoundError{Kind: RoleEntry, ID: ur.Role.GetID()}
 	}
 
 	if err := e.check(Write, ur.Role); err != nil {
@@ -70,6 +74,10 @@ func (e *executor) ModifyUsersForRole(ur *UsersForRole) error {
 		return err
 	}
 
+	if err := isInDomain(ur.Role, currentDomain); err != nil {
+		return err
+	}
+
 	role := ur.Role
 	us := e.e.GetUsersForRoleInDomain(role, currentDomain)
 	uid1 := getIDList(us)
@@ -121,28 +129,43 @@ func (e *executor) ModifyUsersForRole(ur *UsersForRole) error {
 // CreateRole if there does not exist the role, then create a new one
 // 1. create a new role into metadata database
 func (e *executor) CreateRole(role Role) error {
-	return e.createOrRecoverRole(role, e.mdb.CreateRole)
+	return e.createOrRecoverRole(role, e.mdb.CreateRole, nil)
 }
 
 // RecoverRole if there exist the role but soft deleted, then recover it
 // 1. recover the soft delete one role at metadata database
+// 2. restore the role's tombstone rules if it is restore on recover
 func (e *executor) RecoverRole(role Role) error {
-	return e.createOrRecoverRole(role, e.mdb.RecoverRole)
+	fn := func(role Role) error {
+		if err := e.mdb.RecoverRole(role); err != nil {
+			return err
+		}
+		return e.restoreRules(role)
+	}
+
+	return e.createOrRecoverRole(role, fn, e.takeDeletedRole)
 }
 
-// DeleteRole if current user has role's write permission
-// 1. delete all user's g in the domain
-// 2. don't delete any role's g or object's g2 in the domain
-// 3. soft delete one domain in metadata database
-func (e *executor) DeleteRole(domain Domain) error {
-	fn := func(domain Domain) error {
-		if err := e.e.RemoveUsersInDomain(domain); err != nil {
+// DeleteRole if there exist the role and current user has role's write permission
+// 1. deal with the role's users and child roles by the DeleteOption
+// 2. delete the role's g as user, parent or child, and the role's p in current domain
+// 3. archive the deleted g and p as the role's tombstone if there is TombstoneStore
+// 4. soft delete one role in metadata database
+func (e *executor) DeleteRole(role Role, option ...DeleteOption) error {
+	deleteOption, err := getDeleteOption(option)
+	if err != nil {
+		return err
+	}
+
+	fn := func(role Role) error {
+		_, domain, err := e.provider.Get()
+		if err != nil {
 			return err
 		}
-		return e.mdb.DeleteDomainByID(domain.GetID())
+		return e.deleteRole(role, domain, deleteOption)
 	}
 
-	return e.writeDomain(domain, fn)
+	return e.writeRole(role, fn)
 }
 
 // UpdateRole if there exist the role and current user has role's write permission
@@ -151,9 +174,11 @@ func (e *executor) UpdateRole(domain Domain) error {
 	return e.writeDomain(domain, e.mdb.UpdateDomain)
 }
 
-func (e *executor) createOrRecoverRole(role Role, fn func(Role) error) error {
+// createOrRecoverRole check current user has write permission of the role and its parent, then do fn,
+// takeSelf takes the soft deleted role to recover, it is nil to create a new role which is checked by itself
+func (e *executor) createOrRecoverRole(role Role, fn func(Role) error, takeSelf func(uint64, Domain) (Role, error)) error {
 	if err := e.mdb.TakeRole(role); err == nil {
-		return ErrAlreadyExists
+		return &ConflictError{Kind: RoleEntry, ID: role.GetID()}
 	}
 
 	_, domain, err := e.provider.Get()
@@ -161,8 +186,21 @@ func (e *executor) createOrRecoverRole(role Role, fn func(Role) error) error {
 		return err
 	}
 
+	if role.GetDomainID() != 0 {
+		if err := isInDomain(role, domain); err != nil {
+			return err
+		}
+	}
+
 	take := func(id uint64) (parentEntry, error) {
+		if id == role.GetID() {
+			if takeSelf == nil {
+				return role, nil
+			}
+			return takeSelf(id, domain)
+		}
 		r := e.factory.NewRole()
+		r.SetID(id)
 		r.SetDomainID(domain.GetID())
 		err := e.mdb.TakeRole(r)
 		return r, err
@@ -182,16 +220,21 @@ func (e *executor) writeRole(role Role, fn func(Role) error) error {
 	}
 
 	if err := e.mdb.TakeRole(role); err != nil {
-		return ErrNotExists
+		return &NotFoundError{Kind: RoleEntry, ID: role.GetID()}
 	}
 
 	_, domain, err := e.provider.Get()
 	if err != nil {
 		return err
 	}
-	
+
+	if err := isInDomain(role, domain); err != nil {
+		return err
+	}
+
 	take := func(id uint64) (parentEntry, error) {
 		r := e.factory.NewRole()
+		r.SetID(id)
 		r.SetDomainID(domain.GetID())
 		err := e.mdb.TakeRole(r)
 		return r, err
@@ -201,6 +244,85 @@ func (e *executor) writeRole(role Role, fn func(Role) error) error {
 		return err
 	}
 
-	role.SetDomainID(domain.GetID())
-	return fn(domain)
+	return fn(role)
+}
+
+// takeDeletedRole take the soft deleted role by id in the domain, to check it before recovering
+func (e *executor) takeDeletedRole(id uint64, domain Domain) (Role, error) {
+	trash, err := e.trash()
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := trash.GetDeletedRoleInDomain(domain, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if role, ok := getIDMap(roles)[id]; ok {
+		return role.(Role), nil
+	}
+	return nil, &NotFoundError{Kind: RoleEntry, ID: id}
+}
+
+// deleteRole delete the role in the domain
+// 1. DeleteRestrict: fail if the role has users or child roles
+// 2. DeleteCascade: delete all descendant roles if current user has all of their write permission
+// 3. DeleteDetach: re parent child roles to the role's parent
+func (e *executor) deleteRole(role Role, domain Domain, option DeleteOption) error {
+	tree := getTree(e.e.GetRolesInDomain(domain))
+	var children []Role
+	if id := getChildrenID(tree, role.GetID()); len(id) != 0 {
+		var err error
+		if children, err = e.mdb.GetRoleByID(id); err != nil {
+			return err
+		}
+	}
+
+	switch option {
+	case DeleteRestrict:
+		dependents := &DependentsError{
+			Code:  role.Encode(),
+			Users: e.e.GetUsersForRoleInDomain(role, domain),
+			Roles: children,
+		}
+		if !dependents.isEmpty() {
+			return dependents
+		}
+	case DeleteCascade:
+		descendants, err := e.mdb.GetRoleByID(getDescendantsID(tree, role.GetID())[1:])
+		if err != nil {
+			return err
+		}
+		// check all of them before anything is deleted
+		for _, v := range descendants {
+			if err := e.check(Write, v); err != nil {
+				return err
+			}
+		}
+		for _, v := range descendants {
+			if err := e.removeRules(v, roleRules(e.e.GetRulesInDomain(domain), v)); err != nil {
+				return err
+			}
+			if err := e.mdb.DeleteRoleByID(v.GetID()); err != nil {
+				return err
+			}
+		}
+	case DeleteDetach:
+		if parentID, ok := tree[role.GetID()]; ok {
+			parent := e.factory.NewRole()
+			parent.SetID(parentID)
+			for _, v := range children {
+				if err := e.e.AddParentForRoleInDomain(v, parent, domain); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	rules := roleRules(e.e.GetRulesInDomain(domain), role)
+	if err := e.removeRules(role, rules); err != nil {
+		return err
+	}
+	return e.mdb.DeleteRoleByID(role.GetID())
 }
@@ -0,0 +1,256 @@
+package caskin
+
+// ApplyDomainSpec if current user has write permission of every entry to change
+// 1. get current domain's roles, objects, role's tree, object's tree and policies as current spec
+// 2. diff current spec and the spec to get the plan
+// 3. check current user has write permission of every entry in the plan
+// 4. if it is not dry run, apply the plan to metadata database and casbin,
+// if it fails partway, the plan is returned with the error and its applied steps are marked as Applied
+func (e *executor) ApplyDomainSpec(spec *DomainSpec, dryRun bool) (*DomainPlan, error) {
+	if spec == nil {
+		return nil, ErrNil
+	}
+
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+
+	_, currentDomain, err := e.provider.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := e.getDomainSpec(currentDomain)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.checkDomainSpecInDomain(spec, currentDomain); err != nil {
+		return nil, err
+	}
+
+	for _, v := range spec.Roles {
+		v.SetDomainID(currentDomain.GetID())
+	}
+	for _, v := range spec.Objects {
+		v.SetDomainID(currentDomain.GetID())
+	}
+
+	plan := planDomain(current, spec, e.factory, e.option.CompareFields)
+	if err := e.checkDomainPlan(plan, current, spec); err != nil {
+		return nil, err
+	}
+
+	if dryRun {
+		return plan, nil
+	}
+
+	return plan, e.applyDomainPlan(plan, currentDomain)
+}
+
+// getDomainSpec get the domain's current spec
+// 1. get roles, objects from metadata database
+// 2. set role's and object's parent by g and g2 in the domain
+// 3. get policies as p in the domain
+func (e *executor) getDomainSpec(domain Domain) (*DomainSpec, error) {
+	roles, err := e.mdb.GetRoleInDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	roleTree := getTree(e.e.GetRolesInDomain(domain))
+	for _, v := range roles {
+		v.SetParentID(roleTree[v.GetID()])
+	}
+
+	objects, err := e.mdb.GetObjectInDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	objectTree := getTree(e.e.GetObjectsInDomain(domain))
+	for _, v := range objects {
+		v.SetParentID(objectTree[v.GetID()])
+	}
+
+	return &DomainSpec{
+		Roles:    roles,
+		Objects:  objects,
+		Policies: e.e.GetPoliciesInDomain(domain),
+	}, nil
+}
+
+// checkDomainSpecInDomain check the spec's roles and objects existing in metadata database belong to the domain
+func (e *executor) checkDomainSpecInDomain(spec *DomainSpec, domain Domain) error {
+	roles, err := e.mdb.GetRoleByID(getIDList(spec.Roles))
+	if err != nil {
+		return err
+	}
+	for _, v := range roles {
+		if err := isInDomain(v, domain); err != nil {
+			return err
+		}
+	}
+
+	objects, err := e.mdb.GetObjectByID(getIDList(spec.Objects))
+	if err != nil {
+		return err
+	}
+	for _, v := range objects {
+		if err := isInDomain(v, domain); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// checkDomainPlan check write permission of every role and object which the plan touches
+func (e *executor) checkDomainPlan(plan *DomainPlan, specs ...*DomainSpec) error {
+	roles, objects := map[uint64]entry{}, map[uint64]entry{}
+	for _, spec := range specs {
+		for k, v := range getIDMap(spec.Roles) {
+			roles[k] = v
+		}
+		for k, v := range getIDMap(spec.Objects) {
+			objects[k] = v
+		}
+	}
+
+	var toCheck []entry
+	for _, v := range plan.Roles {
+		toCheck = append(toCheck, v.Role)
+	}
+	for _, v := range plan.Objects {
+		toCheck = append(toCheck, v.Object)
+	}
+	take := func(m map[uint64]entry, kind EntryType, id ...uint64) error {
+		for _, v := range id {
+			one, ok := m[v]
+			if !ok {
+				return &NotFoundError{Kind: kind, ID: v}
+			}
+			toCheck = append(toCheck, one)
+		}
+		return nil
+	}
+	for _, v := range plan.RoleParents {
+		if err := take(roles, RoleEntry, v.Role.GetID(), v.Parent.GetID()); err != nil {
+			return err
+		}
+	}
+	for _, v := range plan.ObjectParents {
+		if err := take(objects, ObjectEntry, v.Object.GetID(), v.Parent.GetID()); err != nil {
+			return err
+		}
+	}
+	for _, v := range plan.Policies {
+		if err := take(roles, RoleEntry, v.Policy.Role.GetID()); err != nil {
+			return err
+		}
+		if err := take(objects, ObjectEntry, v.Policy.Object.GetID()); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range toCheck {
+		if err := e.check(Write, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyDomainPlan apply plan to the domain
+// 1. remove p, g, g2 which is to delete
+// 2. delete roles and objects with their g, g2 and p
+// 3. create or update roles and objects
+// 4. add g, g2, p which is to create
+// it is not atomic, if it fails partway, the steps already applied are marked as Applied
+func (e *executor) applyDomainPlan(plan *DomainPlan, domain Domain) error {
+	for _, v := range plan.Policies {
+		if v.Operation == PlanDelete {
+			if err := e.e.RemovePolicyInDomain(v.Policy.Role, v.Policy.Object, domain, v.Policy.Action); err != nil {
+				return err
+			}
+			v.Applied = true
+		}
+	}
+	for _, v := range plan.RoleParents {
+		if v.Operation == PlanDelete {
+			if err := e.e.RemoveParentForRoleInDomain(v.Role, v.Parent, domain); err != nil {
+				return err
+			}
+			v.Applied = true
+		}
+	}
+	for _, v := range plan.ObjectParents {
+		if v.Operation == PlanDelete {
+			if err := e.e.RemoveParentForObjectInDomain(v.Object, v.Parent, domain); err != nil {
+				return err
+			}
+			v.Applied = true
+		}
+	}
+
+	for _, v := range plan.Roles {
+		var err error
+		switch v.Operation {
+		case PlanCreate:
+			err = e.mdb.CreateRole(v.Role)
+		case PlanUpdate:
+			err = e.mdb.UpdateRole(v.Role)
+		case PlanDelete:
+			if err = e.e.RemoveRoleInDomain(v.Role, domain); err == nil {
+				err = e.mdb.DeleteRoleByID(v.Role.GetID())
+			}
+		}
+		if err != nil {
+			return err
+		}
+		v.Applied = true
+	}
+	for _, v := range plan.Objects {
+		var err error
+		switch v.Operation {
+		case PlanCreate:
+			err = e.mdb.CreateObject(v.Object)
+		case PlanUpdate:
+			err = e.mdb.UpdateObject(v.Object)
+		case PlanDelete:
+			if err = e.e.RemoveObjectInDomain(v.Object, domain); err == nil {
+				err = e.mdb.DeleteObjectByID(v.Object.GetID())
+			}
+		}
+		if err != nil {
+			return err
+		}
+		v.Applied = true
+	}
+
+	for _, v := range plan.RoleParents {
+		if v.Operation == PlanCreate {
+			if err := e.e.AddParentForRoleInDomain(v.Role, v.Parent, domain); err != nil {
+				return err
+			}
+			v.Applied = true
+		}
+	}
+	for _, v := range plan.ObjectParents {
+		if v.Operation == PlanCreate {
+			if err := e.e.AddParentForObjectInDomain(v.Object, v.Parent, domain); err != nil {
+				return err
+			}
+			v.Applied = true
+		}
+	}
+	for _, v := range plan.Policies {
+		if v.Operation == PlanCreate {
+			if err := e.e.AddPolicyInDomain(v.Policy.Role, v.Policy.Object, domain, v.Policy.Action); err != nil {
+				return err
+			}
+			v.Applied = true
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,298 @@
+package caskin
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// MigrateDomains if current user is superadmin
+// 1. get every domain's template version, the domain must be VersionedDomain if there are DomainTemplates,
+// otherwise it is always 0 which is DomainCreator
+// 2. get the latest template's roles, objects and policies missing in the domain as to be added
+// 3. get the domain's template's roles, objects and policies not in the latest template as to be removed
+// 4. the custom ones not from the domain's template are left alone
+// 5. if it is not dry run, apply them and update the domain's template version
+func (e *executor) MigrateDomains(dryRun bool) ([]*DomainMigration, error) {
+	if err := e.checkSuperadmin(); err != nil {
+		return nil, err
+	}
+
+	domains, err := e.mdb.GetAllDomain()
+	if err != nil {
+		return nil, err
+	}
+
+	var migrations []*DomainMigration
+	for _, v := range domains {
+		if v.Encode() == SuperadminDomain {
+			continue
+		}
+
+		m := e.planDomainMigration(v)
+		if m.Error == nil && !dryRun {
+			m.Error = e.applyDomainMigration(m)
+		}
+		migrations = append(migrations, m)
+	}
+
+	return migrations, nil
+}
+
+func (e *executor) planDomainMigration(domain Domain) *DomainMigration {
+	latest := e.option.GetLatestDomainTemplate()
+	m := &DomainMigration{
+		Domain:    domain,
+		ToVersion: latest.Version,
+	}
+	if d, ok := domain.(VersionedDomain); ok {
+		m.FromVersion = d.GetTemplateVersion()
+	} else if len(e.option.DomainTemplates) != 0 {
+		m.Error = fmt.Errorf("%w: %v", ErrDomainIsNotVersioned, domain.Encode())
+		return m
+	}
+
+	current, err := e.takeDomainTemplate(latest, domain)
+	if err != nil {
+		m.Error = err
+		return m
+	}
+	old, err := e.takeDomainTemplate(e.option.GetDomainTemplate(m.FromVersion), domain)
+	if err != nil {
+		m.Error = err
+		return m
+	}
+
+	live := &DomainSpec{Policies: e.e.GetPoliciesInDomain(domain)}
+	m.Added, m.Removed = templateDrift(current, old, live)
+	return m
+}
+
+// takeDomainTemplate get the template's roles, objects and policies of the domain,
+// and fill in id of those which exist in metadata database
+// 1. they are matched with the domain's roles and objects by TemplateKeyFields
+// 2. they are matched by DefaultTemplateKeyFields if there is no TemplateKeyFields,
+// or by all their non-zero fields if they don't have DefaultTemplateKeyFields
+func (e *executor) takeDomainTemplate(template *DomainTemplate, domain Domain) (*DomainSpec, error) {
+	spec := &DomainSpec{}
+	if template == nil || template.Creator == nil {
+		return spec, nil
+	}
+
+	spec.Roles, spec.Objects, spec.Policies = template.Creator(domain)
+	for _, v := range spec.Roles {
+		v.SetDomainID(domain.GetID())
+	}
+	for _, v := range spec.Objects {
+		v.SetDomainID(domain.GetID())
+	}
+
+	roles, err := e.mdb.GetRoleInDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	var liveRoles []entry
+	for _, v := range roles {
+		liveRoles = append(liveRoles, v)
+	}
+	for _, v := range spec.Roles {
+		if l := matchByKeyFields(v, liveRoles, e.templateKeyFields(v)); l != nil {
+			liveRoles = removeEntry(liveRoles, l.GetID())
+			v.SetID(l.GetID())
+		}
+	}
+
+	objects, err := e.mdb.GetObjectInDomain(domain)
+	if err != nil {
+		return nil, err
+	}
+	var liveObjects []entry
+	for _, v := range objects {
+		liveObjects = append(liveObjects, v)
+	}
+	for _, v := range spec.Objects {
+		if l := matchByKeyFields(v, liveObjects, e.templateKeyFields(v)); l != nil {
+			liveObjects = removeEntry(liveObjects, l.GetID())
+			v.SetID(l.GetID())
+		}
+	}
+
+	return spec, nil
+}
+
+// templateKeyFields get the fields to match the template's entry,
+// they are TemplateKeyFields, or DefaultTemplateKeyFields if the entry has them,
+// or all the entry's non-zero fields except DefaultCompareIgnoredFields
+func (e *executor) templateKeyFields(one entry) []string {
+	if len(e.option.TemplateKeyFields) != 0 {
+		return e.option.TemplateKeyFields
+	}
+
+	m := entryToMap(one)
+	hasDefault := len(DefaultTemplateKeyFields) != 0
+	for _, k := range DefaultTemplateKeyFields {
+		if _, ok := m[k]; !ok {
+			hasDefault = false
+		}
+	}
+	if hasDefault {
+		return DefaultTemplateKeyFields
+	}
+
+	var fields []string
+	for _, k := range allComparedFields(m) {
+		if v := m[k]; v != nil && !reflect.ValueOf(v).IsZero() {
+			fields = append(fields, k)
+		}
+	}
+	return fields
+}
+
+// applyDomainMigration apply the migration to the domain
+// 1. upsert added roles and objects, add added policies
+// 2. remove removed policies, delete removed roles and objects with their g, g2, p
+// 3. add domain admin role's g and update the domain's template version
+func (e *executor) applyDomainMigration(m *DomainMigration) error {
+	domain := m.Domain
+	for _, v := range m.Added.Roles {
+		if err := e.mdb.UpsertRole(v); err != nil {
+			return err
+		}
+	}
+	for _, v := range m.Added.Objects {
+		if err := e.mdb.UpsertObject(v); err != nil {
+			return err
+		}
+	}
+	for _, v := range m.Added.Policies {
+		if err := e.e.AddPolicyInDomain(v.Role, v.Object, domain, v.Action); err != nil {
+			return err
+		}
+	}
+
+	for _, v := range m.Removed.Policies {
+		if err := e.e.RemovePolicyInDomain(v.Role, v.Object, domain, v.Action); err != nil {
+			return err
+		}
+	}
+	for _, v := range m.Removed.Roles {
+		if err := e.e.RemoveRoleInDomain(v, domain); err != nil {
+			return err
+		}
+		if err := e.mdb.DeleteRoleByID(v.GetID()); err != nil {
+			return err
+		}
+	}
+	for _, v := range m.Removed.Objects {
+		if err := e.e.RemoveObjectInDomain(v, domain); err != nil {
+			return err
+		}
+		if err := e.mdb.DeleteObjectByID(v.GetID()); err != nil {
+			return err
+		}
+	}
+
+	latest, err := e.takeDomainTemplate(e.option.GetLatestDomainTemplate(), domain)
+	if err != nil {
+		return err
+	}
+	if role := e.option.GetDomainAdminRole(latest.Roles); role != nil && role.GetID() != 0 {
+		if err := e.e.AddDomainAdminRoleInDomain(role, domain); err != nil {
+			return err
+		}
+	}
+
+	return e.updateDomainTemplateVersion(domain, m.ToVersion)
+}
+
+// CheckDomainDrift if there exist the domain, and it is current domain or current user is superadmin
+// 1. get the latest template's roles, objects and policies of the domain, which are matched by TemplateKeyFields
+// 2. template's entries existing in the domain are modified if any of CompareFields differs, or any field if there is no CompareFields
+// 3. template's entries not in the domain are missing, and the domain's entries not in template are extra
+func (e *executor) CheckDomainDrift(domain Domain) (*DomainDrift, error) {
+	if err := isValid(domain); err != nil {
+		return nil, err
+	}
+
+	if err := e.mdb.TakeDomain(domain); err != nil {
+		return nil, &NotFoundError{Kind: DomainEntry, ID: domain.GetID()}
+	}
+
+	if err := e.checkDomain(domain); err != nil {
+		return nil, err
+	}
+
+	latest := e.option.GetLatestDomainTemplate()
+	template, err := e.takeDomainTemplate(latest, domain)
+	if err != nil {
+		return nil, err
+	}
+	live, err := e.getDomainSpec(domain)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := &DomainDrift{
+		Domain:  domain,
+		Version: latest.Version,
+		Missing: &DomainSpec{},
+		Extra:   &DomainSpec{},
+	}
+
+	liveRoles, templateRoles := getIDMap(live.Roles), getIDMap(template.Roles)
+	for _, v := range template.Roles {
+		l, ok := liveRoles[v.GetID()]
+		if !ok {
+			drift.Missing.Roles = append(drift.Missing.Roles, v)
+			continue
+		}
+		if isEntryModified(l.(Role), v, e.option.CompareFields) {
+			drift.ModifiedRoles = append(drift.ModifiedRoles, &RoleDrift{Template: v, Live: l.(Role)})
+		}
+	}
+	for _, v := range live.Roles {
+		if _, ok := templateRoles[v.GetID()]; !ok {
+			drift.Extra.Roles = append(drift.Extra.Roles, v)
+		}
+	}
+
+	liveObjects, templateObjects := getIDMap(live.Objects), getIDMap(template.Objects)
+	for _, v := range template.Objects {
+		l, ok := liveObjects[v.GetID()]
+		if !ok {
+			drift.Missing.Objects = append(drift.Missing.Objects, v)
+			continue
+		}
+		if isEntryModified(l.(Object), v, e.option.CompareFields) {
+			drift.ModifiedObjects = append(drift.ModifiedObjects, &ObjectDrift{Template: v, Live: l.(Object)})
+		}
+	}
+	for _, v := range live.Objects {
+		if _, ok := templateObjects[v.GetID()]; !ok {
+			drift.Extra.Objects = append(drift.Extra.Objects, v)
+		}
+	}
+
+	templatePolicies, livePolicies := policyKeyMap(template.Policies), policyKeyMap(live.Policies)
+	for _, v := range template.Policies {
+		if _, ok := livePolicies[toPolicyKey(v)]; !ok || v.Role.GetID() == 0 || v.Object.GetID() == 0 {
+			drift.Missing.Policies = append(drift.Missing.Policies, v)
+		}
+	}
+	for _, v := range live.Policies {
+		if _, ok := templatePolicies[toPolicyKey(v)]; !ok {
+			drift.Extra.Policies = append(drift.Extra.Policies, v)
+		}
+	}
+
+	return drift, nil
+}
+
+func removeEntry(source []entry, id uint64) []entry {
+	var out []entry
+	for _, v := range source {
+		if v.GetID() != id {
+			out = append(out, v)
+		}
+	}
+	return out
+}
@@ -0,0 +1,87 @@
+package caskin
+
+import (
+	"errors"
+	"time"
+)
+
+// PurgeTombstones if current user is superadmin
+// 1. delete tombstones which are older than the retention of TombstoneOption
+// 2. do nothing if there is no TombstoneStore or the retention is 0
+func (e *executor) PurgeTombstones() error {
+	if err := e.checkSuperadmin(); err != nil {
+		return err
+	}
+
+	store := e.option.GetTombstoneStore()
+	if store == nil || e.option.TombstoneOption.Retention == 0 {
+		return nil
+	}
+
+	return store.DeleteTombstoneBefore(time.Now().Add(-e.option.TombstoneOption.Retention))
+}
+
+// removeRules remove rules from casbin, and archive them as the entry's tombstone if there is TombstoneStore
+func (e *executor) removeRules(one entry, rules []*Rule) error {
+	if len(rules) == 0 {
+		return nil
+	}
+
+	if store := e.option.GetTombstoneStore(); store != nil {
+		tombstone := &Tombstone{
+			Code:      one.Encode(),
+			Rules:     rules,
+			DeletedAt: time.Now(),
+		}
+		if err := store.SaveTombstone(tombstone); err != nil {
+			return err
+		}
+	}
+
+	return e.e.RemoveRules(rules)
+}
+
+// restoreRules add the entry's tombstone's rules back to casbin if it is restore on recover,
+// and current user has the entry's write permission, or is superadmin if it is domain,
+// then the tombstone is deleted
+func (e *executor) restoreRules(one entry) error {
+	if !e.option.IsRestoreOnRecover() {
+		return nil
+	}
+
+	if err := e.checkRestore(one); err != nil {
+		return err
+	}
+
+	store := e.option.GetTombstoneStore()
+	tombstone, err := store.TakeTombstone(one.Encode())
+	if errors.Is(err, ErrNotExists) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := e.e.AddRules(tombstone.Rules); err != nil {
+		return err
+	}
+
+	return store.DeleteTombstone(tombstone.Code)
+}
+
+// checkRestore a domain's tombstone has all of its users' rules, but domain is not an object
+// whose permission could be checked, so only superadmin could restore it
+func (e *executor) checkRestore(one entry) error {
+	if !one.IsObject() {
+		return e.checkSuperadmin()
+	}
+	return e.check(Write, one)
+}
+
+// deleteTombstone delete the entry's tombstone if there is TombstoneStore
+func (e *executor) deleteTombstone(one entry) error {
+	if store := e.option.GetTombstoneStore(); store != nil {
+		return store.DeleteTombstone(one.Encode())
+	}
+	return nil
+}
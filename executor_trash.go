@@ -0,0 +1,259 @@
+package caskin
+
+import "time"
+
+// ListDeletedRoles if current user has read permission
+// 1. get soft deleted roles in current domain
+// 2. filter them by read permission
+func (e *executor) ListDeletedRoles() ([]Role, error) {
+	trash, err := e.trash()
+	if err != nil {
+		return nil, err
+	}
+
+	_, currentDomain, err := e.provider.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := trash.GetDeletedRoleInDomain(currentDomain, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := e.filter(Read, roles)
+	if err != nil {
+		return nil, err
+	}
+	return out.([]Role), nil
+}
+
+// ListDeletedObjects if current user has read permission
+// 1. get soft deleted objects in current domain
+// 2. filter them by read permission
+func (e *executor) ListDeletedObjects() ([]Object, error) {
+	trash, err := e.trash()
+	if err != nil {
+		return nil, err
+	}
+
+	_, currentDomain, err := e.provider.Get()
+	if err != nil {
+		return nil, err
+	}
+
+	objects, err := trash.GetDeletedObjectInDomain(currentDomain, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := e.filter(Read, objects)
+	if err != nil {
+		return nil, err
+	}
+	return out.([]Object), nil
+}
+
+// ListDeletedDomains if current user is superadmin
+// 1. get all soft deleted domains
+func (e *executor) ListDeletedDomains() ([]Domain, error) {
+	trash, err := e.trash()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := e.checkSuperadmin(); err != nil {
+		return nil, err
+	}
+
+	return trash.GetAllDeletedDomain(time.Now())
+}
+
+// Purge if there exist the soft deleted role, object in current domain or domain by the entry type and id,
+// and current user has its write permission, or is superadmin if it is domain
+// 1. remove its g, g2, p and tombstone from casbin, all of the domain's if it is domain
+// 2. hard delete it in metadata database, with all of the domain's roles and objects if it is domain
+func (e *executor) Purge(entryType EntryType, id uint64) error {
+	if id == 0 {
+		return ErrEmptyID
+	}
+
+	trash, err := e.trash()
+	if err != nil {
+		return err
+	}
+
+	if entryType == DomainEntry {
+		if err := e.checkSuperadmin(); err != nil {
+			return err
+		}
+	}
+
+	_, currentDomain, err := e.provider.Get()
+	if err != nil {
+		return err
+	}
+
+	source, err := getDeleted(trash, entryType, currentDomain, time.Now())
+	if err != nil {
+		return err
+	}
+
+	deleted, ok := getIDMap(source)[id]
+	if !ok {
+		return &NotFoundError{Kind: entryType, ID: id}
+	}
+
+	if err := e.check(Write, deleted); err != nil {
+		return err
+	}
+
+	switch entryType {
+	case RoleEntry:
+		return e.purgeRole(trash, deleted.(Role), currentDomain)
+	case ObjectEntry:
+		return e.purgeObject(trash, deleted.(Object), currentDomain)
+	default:
+		return e.purgeDomain(trash, deleted.(Domain))
+	}
+}
+
+// PurgeOlderThan if current user is superadmin
+// 1. purge roles and objects soft deleted before the retention in every domain
+// 2. purge domains soft deleted before the retention
+func (e *executor) PurgeOlderThan(retention time.Duration) error {
+	trash, err := e.trash()
+	if err != nil {
+		return err
+	}
+
+	if err := e.checkSuperadmin(); err != nil {
+		return err
+	}
+
+	before := time.Now().Add(-retention)
+	domains, err := e.mdb.GetAllDomain()
+	if err != nil {
+		return err
+	}
+
+	for _, domain := range domains {
+		roles, err := trash.GetDeletedRoleInDomain(domain, before)
+		if err != nil {
+			return err
+		}
+		for _, v := range roles {
+			if err := e.purgeRole(trash, v, domain); err != nil {
+				return err
+			}
+		}
+
+		objects, err := trash.GetDeletedObjectInDomain(domain, before)
+		if err != nil {
+			return err
+		}
+		for _, v := range objects {
+			if err := e.purgeObject(trash, v, domain); err != nil {
+				return err
+			}
+		}
+	}
+
+	deleted, err := trash.GetAllDeletedDomain(before)
+	if err != nil {
+		return err
+	}
+	for _, v := range deleted {
+		if err := e.purgeDomain(trash, v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// trash get the TrashMetaDB API of metadata database, it is ErrTrashIsNotSupported if there is none
+func (e *executor) trash() (TrashMetaDB, error) {
+	if trash, ok := e.mdb.(TrashMetaDB); ok {
+		return trash, nil
+	}
+	return nil, ErrTrashIsNotSupported
+}
+
+func getDeleted(trash TrashMetaDB, entryType EntryType, domain Domain, before time.Time) (interface{}, error) {
+	switch entryType {
+	case RoleEntry:
+		return trash.GetDeletedRoleInDomain(domain, before)
+	case ObjectEntry:
+		return trash.GetDeletedObjectInDomain(domain, before)
+	case DomainEntry:
+		return trash.GetAllDeletedDomain(before)
+	default:
+		return nil, ErrInvalidEntryType
+	}
+}
+
+func (e *executor) purgeRole(trash TrashMetaDB, role Role, domain Domain) error {
+	if err := e.e.RemoveRules(roleRules(e.e.GetRulesInDomain(domain), role)); err != nil {
+		return err
+	}
+	if err := e.deleteTombstone(role); err != nil {
+		return err
+	}
+	return trash.PurgeRoleByID(role.GetID())
+}
+
+func (e *executor) purgeObject(trash TrashMetaDB, object Object, domain Domain) error {
+	if err := e.e.RemoveRules(objectRules(e.e.GetRulesInDomain(domain), object)); err != nil {
+		return err
+	}
+	if err := e.deleteTombstone(object); err != nil {
+		return err
+	}
+	return trash.PurgeObjectByID(object.GetID())
+}
+
+func (e *executor) purgeDomain(trash TrashMetaDB, domain Domain) error {
+	if err := e.e.RemoveRules(e.e.GetRulesInDomain(domain)); err != nil {
+		return err
+	}
+
+	roles, err := e.mdb.GetRoleInDomain(domain)
+	if err != nil {
+		return err
+	}
+	deletedRoles, err := trash.GetDeletedRoleInDomain(domain, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, v := range append(roles, deletedRoles...) {
+		if err := e.deleteTombstone(v); err != nil {
+			return err
+		}
+		if err := trash.PurgeRoleByID(v.GetID()); err != nil {
+			return err
+		}
+	}
+
+	objects, err := e.mdb.GetObjectInDomain(domain)
+	if err != nil {
+		return err
+	}
+	deletedObjects, err := trash.GetDeletedObjectInDomain(domain, time.Now())
+	if err != nil {
+		return err
+	}
+	for _, v := range append(objects, deletedObjects...) {
+		if err := e.deleteTombstone(v); err != nil {
+			return err
+		}
+		if err := trash.PurgeObjectByID(v.GetID()); err != nil {
+			return err
+		}
+	}
+
+	if err := e.deleteTombstone(domain); err != nil {
+		return err
+	}
+	return trash.PurgeDomainByID(domain.GetID())
+}
@@ -0,0 +1,160 @@
+package caskin
+
+// Explanation why the user can or can't do the action on the object in the domain
+type Explanation struct {
+	User    User
+	Object  Object
+	Domain  Domain
+	Action  Action
+	Allowed bool
+	// the user is superadmin
+	Superadmin bool
+	// the user is domain admin of the domain
+	DomainAdmin bool
+	// the policies giving the permission
+	Grants []*Grant
+}
+
+// Grant a policy reached by the user's role and the object
+type Grant struct {
+	// from the role of the user's g to the role of the policy, parent role inherits child role's policies
+	Roles []Role
+	// from the object to the object of the policy, child object inherits parent object's policies
+	Objects []Object
+	Policy  *Policy
+}
+
+// Explain explain the user's action on the object in the domain
+// 1. check superadmin and domain admin
+// 2. find the policies of the action on the object or its ancestors,
+// whose role is the user's role or its descendants
+func Explain(e ienforcer, user User, object Object, domain Domain, action Action) (*Explanation, error) {
+	allowed, err := e.Enforce(user, object, domain, action)
+	if err != nil {
+		return nil, err
+	}
+	superadmin, err := e.IsSuperAdmin(user)
+	if err != nil {
+		return nil, err
+	}
+	domainAdmin, err := e.IsDomainAdmin(user, domain)
+	if err != nil {
+		return nil, err
+	}
+
+	explanation := &Explanation{
+		User:        user,
+		Object:      object,
+		Domain:      domain,
+		Action:      action,
+		Allowed:     allowed,
+		Superadmin:  superadmin,
+		DomainAdmin: domainAdmin,
+	}
+
+	rs, os := e.GetRolesInDomain(domain), e.GetObjectsInDomain(domain)
+	roleTree, roleMap := getTree(rs), getIDMap(rs)
+	objectMap := getIDMap(os)
+	objectMap[object.GetID()] = object
+	objects := getAncestorsID(getTree(os), object.GetID())
+	direct := getIDMap(e.GetRolesForUserInDomain(user, domain))
+
+	for _, p := range e.GetPoliciesInDomain(domain) {
+		if p.Action != action {
+			continue
+		}
+		o := indexOfID(objects, p.Object.GetID())
+		if o < 0 {
+			continue
+		}
+
+		// the policy's role and its ancestors, the nearest one assigned to the user wins
+		if _, ok := roleMap[p.Role.GetID()]; !ok {
+			roleMap[p.Role.GetID()] = p.Role
+		}
+		roles := getAncestorsID(roleTree, p.Role.GetID())
+		for r, id := range roles {
+			if _, ok := direct[id]; !ok {
+				continue
+			}
+			grant := &Grant{Policy: p}
+			for i := r; i >= 0; i-- {
+				grant.Roles = append(grant.Roles, roleMap[roles[i]].(Role))
+			}
+			for _, v := range objects[:o+1] {
+				grant.Objects = append(grant.Objects, objectMap[v].(Object))
+			}
+			explanation.Grants = append(explanation.Grants, grant)
+			break
+		}
+	}
+
+	return explanation, nil
+}
+
+// WhoCan get the roles and their users who can do the action on the object in the domain,
+// superadmin and domain admin can do everything so they are not included
+// 1. the roles of the action's policies on the object or its ancestors
+// 2. the ancestors of these roles inherit their policies
+// 3. the users of all these roles
+func WhoCan(e ienforcer, object Object, domain Domain, action Action) ([]Role, []User) {
+	rs := e.GetRolesInDomain(domain)
+	roleTree, roleMap := getTree(rs), getIDMap(rs)
+	objects := getAncestorsID(getTree(e.GetObjectsInDomain(domain)), object.GetID())
+
+	seen := map[uint64]bool{}
+	var roles []Role
+	for _, p := range e.GetPoliciesInDomain(domain) {
+		if p.Action != action || indexOfID(objects, p.Object.GetID()) < 0 {
+			continue
+		}
+		for _, id := range getAncestorsID(roleTree, p.Role.GetID()) {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+			if id == p.Role.GetID() {
+				roles = append(roles, p.Role)
+			} else {
+				roles = append(roles, roleMap[id].(Role))
+			}
+		}
+	}
+
+	userSeen := map[uint64]bool{}
+	var users []User
+	for _, r := range roles {
+		for _, u := range e.GetUsersForRoleInDomain(r, domain) {
+			if !userSeen[u.GetID()] {
+				userSeen[u.GetID()] = true
+				users = append(users, u)
+			}
+		}
+	}
+
+	return roles, users
+}
+
+// getAncestorsID get the id and its ancestors' id from near to far in the tree of child to parent
+func getAncestorsID(tree map[uint64]uint64, id uint64) []uint64 {
+	out := []uint64{id}
+	seen := map[uint64]bool{id: true}
+	for {
+		parent, ok := tree[id]
+		if !ok || seen[parent] {
+			return out
+		}
+		seen[parent] = true
+		out = append(out, parent)
+		id = parent
+	}
+}
+
+func indexOfID(id []uint64, target uint64) int {
+	for i, v := range id {
+		if v == target {
+			return i
+		}
+	}
+	return -1
+}
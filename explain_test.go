@@ -0,0 +1,74 @@
+package caskin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+// codes of the entries, such as "[role_1 role_2]"
+func codes(entries ...interface{ Encode() string }) string {
+	var out []string
+	for _, v := range entries {
+		out = append(out, v.Encode())
+	}
+	return fmt.Sprint(out)
+}
+
+func TestExplain(t *testing.T) {
+	s := newStage(t, nil)
+	s.assign(t, 3, 1)
+	s.assign(t, 4, 2)
+	e := caskin.NewEnforcer(s.e, example.NewEntryFactory())
+
+	// admin inherits member's read on root, and data is under root
+	explanation, err := caskin.Explain(e, &example.User{ID: 3}, &example.Object{ID: 2}, s.domain, caskin.Read)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !explanation.Allowed || explanation.Superadmin || len(explanation.Grants) != 1 {
+		t.Fatal(explanation)
+	}
+	grant := explanation.Grants[0]
+	if len(grant.Roles) != 2 || len(grant.Objects) != 2 {
+		t.Fatal(grant.Roles, grant.Objects)
+	}
+	if roles, objects := codes(grant.Roles[0], grant.Roles[1]), codes(grant.Objects[0], grant.Objects[1]); roles != "[role_1 role_2]" || objects != "[object_2 object_1]" {
+		t.Fatal(roles, objects)
+	}
+
+	explanation, err = caskin.Explain(e, &example.User{ID: 4}, &example.Object{ID: 2}, s.domain, caskin.Write)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if explanation.Allowed || len(explanation.Grants) != 0 {
+		t.Fatal(explanation)
+	}
+
+	explanation, err = caskin.Explain(e, &example.User{ID: 1}, &example.Object{ID: 2}, s.domain, caskin.Write)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !explanation.Allowed || !explanation.Superadmin || len(explanation.Grants) != 0 {
+		t.Fatal(explanation)
+	}
+}
+
+func TestWhoCan(t *testing.T) {
+	s := newStage(t, nil)
+	s.assign(t, 3, 1)
+	s.assign(t, 4, 2)
+	e := caskin.NewEnforcer(s.e, example.NewEntryFactory())
+
+	roles, users := caskin.WhoCan(e, &example.Object{ID: 2}, s.domain, caskin.Read)
+	if len(roles) != 2 || codes(roles[0], roles[1]) != "[role_2 role_1]" || len(users) != 2 || codes(users[0], users[1]) != "[user_4 user_3]" {
+		t.Fatal(roles, users)
+	}
+	// superadmin is not included
+	roles, users = caskin.WhoCan(e, &example.Object{ID: 2}, s.domain, caskin.Write)
+	if len(roles) != 1 || roles[0].Encode() != "role_1" || len(users) != 1 || users[0].Encode() != "user_3" {
+		t.Fatal(roles, users)
+	}
+}
@@ -5,5 +5,12 @@ go 1.15
 require (
 	github.com/ahmetb/go-linq/v3 v3.2.0
 	github.com/casbin/casbin/v2 v2.22.0
+	github.com/golang/protobuf v1.4.2
+	github.com/peterh/liner v1.2.1
+	google.golang.org/genproto v0.0.0-20200526211855-cb27e3aa2013
+	google.golang.org/grpc v1.38.0
+	google.golang.org/protobuf v1.25.0
+	gorm.io/driver/sqlite v1.1.4
 	gorm.io/gorm v1.20.12
+	sigs.k8s.io/yaml v1.2.0
 )
@@ -0,0 +1,299 @@
+package caskin
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type GraphEdgeType string
+
+const (
+	// user has the role
+	AssignEdge GraphEdgeType = "assign"
+	// parent role inherits child role's policies
+	InheritEdge GraphEdgeType = "inherit"
+	// child object inherits parent object's policies
+	ContainEdge GraphEdgeType = "contain"
+	// role's actions on the object
+	PolicyEdge GraphEdgeType = "policy"
+)
+
+// GraphOption the filters and labels of the domain's graph
+type GraphOption struct {
+	// only the user, its roles and the roles they inherit, with these roles' policies and objects
+	User User
+	// only the object and its descendants, with the policies on them, their roles, the roles' ancestors and users
+	Object Object
+	// keys of the entry's JSON metadata shown in the node's label after its code, such as "name"
+	LabelKeys []string
+}
+
+// Graph users, role's tree, object's tree and policies of a domain
+type Graph struct {
+	Domain Domain
+	Nodes  []*GraphNode
+	Edges  []*GraphEdge
+}
+
+// GraphNode a user, role or object of the graph, ID is the entry's code
+type GraphNode struct {
+	ID    string
+	Type  EntryType
+	Label string
+}
+
+// GraphEdge an edge between nodes' ID, actions of the policy edge are joined as its label
+type GraphEdge struct {
+	From  string
+	To    string
+	Type  GraphEdgeType
+	Label string
+}
+
+// newGraph build the graph of the archive and its users' metadata
+// 1. get the kept users, roles and objects by the option's filters
+// 2. add the nodes and the edges between kept nodes
+func newGraph(archive *DomainArchive, users []User, option *GraphOption) *Graph {
+	if option == nil {
+		option = &GraphOption{}
+	}
+
+	roleTree, objectTree := getTree(archive.Roles), getTree(archive.Objects)
+	userMap := getIDMap(users)
+	for _, v := range archive.RolesForUsers {
+		if _, ok := userMap[v.User.GetID()]; !ok {
+			userMap[v.User.GetID()] = v.User
+			users = append(users, v.User)
+		}
+	}
+	sort.SliceStable(users, func(i, j int) bool {
+		return users[i].GetID() < users[j].GetID()
+	})
+
+	keptUser := map[uint64]bool{}
+	keptRole := map[uint64]bool{}
+	keptObject := map[uint64]bool{}
+	for _, v := range users {
+		keptUser[v.GetID()] = true
+	}
+	for _, v := range archive.Roles {
+		keptRole[v.GetID()] = true
+	}
+	for _, v := range archive.Objects {
+		keptObject[v.GetID()] = true
+	}
+
+	if option.User != nil {
+		u, r, o := map[uint64]bool{option.User.GetID(): true}, map[uint64]bool{}, map[uint64]bool{}
+		for _, v := range archive.RolesForUsers {
+			if v.User.GetID() != option.User.GetID() {
+				continue
+			}
+			for _, role := range v.Roles {
+				for _, id := range getDescendantsID(roleTree, role.GetID()) {
+					r[id] = true
+				}
+			}
+		}
+		for _, p := range archive.Policies {
+			if r[p.Role.GetID()] {
+				for _, id := range getDescendantsID(objectTree, p.Object.GetID()) {
+					o[id] = true
+				}
+			}
+		}
+		intersectID(keptUser, u)
+		intersectID(keptRole, r)
+		intersectID(keptObject, o)
+	}
+
+	if option.Object != nil {
+		u, r, o := map[uint64]bool{}, map[uint64]bool{}, map[uint64]bool{}
+		for _, id := range getDescendantsID(objectTree, option.Object.GetID()) {
+			o[id] = true
+		}
+		for _, p := range archive.Policies {
+			if o[p.Object.GetID()] {
+				for _, id := range getAncestorsID(roleTree, p.Role.GetID()) {
+					r[id] = true
+				}
+			}
+		}
+		for _, v := range archive.RolesForUsers {
+			for _, role := range v.Roles {
+				if r[role.GetID()] {
+					u[v.User.GetID()] = true
+				}
+			}
+		}
+		intersectID(keptUser, u)
+		intersectID(keptRole, r)
+		intersectID(keptObject, o)
+	}
+
+	g := &Graph{Domain: archive.Domain}
+	roleCode, objectCode := map[uint64]string{}, map[uint64]string{}
+	for _, v := range users {
+		if keptUser[v.GetID()] {
+			g.Nodes = append(g.Nodes, &GraphNode{ID: v.Encode(), Type: UserEntry, Label: graphLabel(v, option.LabelKeys)})
+		}
+	}
+	for _, v := range archive.Roles {
+		roleCode[v.GetID()] = v.Encode()
+		if keptRole[v.GetID()] {
+			g.Nodes = append(g.Nodes, &GraphNode{ID: v.Encode(), Type: RoleEntry, Label: graphLabel(v, option.LabelKeys)})
+		}
+	}
+	for _, v := range archive.Objects {
+		objectCode[v.GetID()] = v.Encode()
+		if keptObject[v.GetID()] {
+			g.Nodes = append(g.Nodes, &GraphNode{ID: v.Encode(), Type: ObjectEntry, Label: graphLabel(v, option.LabelKeys)})
+		}
+	}
+
+	for _, v := range archive.RolesForUsers {
+		for _, role := range v.Roles {
+			if keptUser[v.User.GetID()] && keptRole[role.GetID()] {
+				g.Edges = append(g.Edges, &GraphEdge{From: v.User.Encode(), To: roleCode[role.GetID()], Type: AssignEdge})
+			}
+		}
+	}
+	for _, v := range archive.Roles {
+		if keptRole[v.GetID()] && keptRole[v.GetParentID()] {
+			g.Edges = append(g.Edges, &GraphEdge{From: roleCode[v.GetParentID()], To: v.Encode(), Type: InheritEdge})
+		}
+	}
+	for _, v := range archive.Objects {
+		if keptObject[v.GetID()] && keptObject[v.GetParentID()] {
+			g.Edges = append(g.Edges, &GraphEdge{From: objectCode[v.GetParentID()], To: v.Encode(), Type: ContainEdge})
+		}
+	}
+
+	// one edge for all actions of the role on the object
+	policies := map[[2]uint64]*GraphEdge{}
+	for _, p := range archive.Policies {
+		if !keptRole[p.Role.GetID()] || !keptObject[p.Object.GetID()] {
+			continue
+		}
+		key := [2]uint64{p.Role.GetID(), p.Object.GetID()}
+		if edge, ok := policies[key]; ok {
+			edge.Label += "," + string(p.Action)
+			continue
+		}
+		edge := &GraphEdge{From: roleCode[p.Role.GetID()], To: objectCode[p.Object.GetID()], Type: PolicyEdge, Label: string(p.Action)}
+		policies[key] = edge
+		g.Edges = append(g.Edges, edge)
+	}
+
+	return g
+}
+
+// DOT encode the graph to Graphviz DOT
+func (g *Graph) DOT() string {
+	shape := map[EntryType]string{UserEntry: "ellipse", RoleEntry: "box", ObjectEntry: "folder"}
+	style := map[GraphEdgeType]string{AssignEdge: "solid", InheritEdge: "dashed", ContainEdge: "dotted", PolicyEdge: "bold"}
+
+	b := &strings.Builder{}
+	name := "caskin"
+	if g.Domain != nil {
+		name = g.Domain.Encode()
+	}
+	fmt.Fprintf(b, "digraph %v {\n\trankdir=LR;\n", dotQuote(name))
+	for _, v := range g.Nodes {
+		fmt.Fprintf(b, "\t%v [label=%v, shape=%v];\n", dotQuote(v.ID), dotQuote(v.Label), shape[v.Type])
+	}
+	for _, v := range g.Edges {
+		label := v.Label
+		if label == "" {
+			label = string(v.Type)
+		}
+		fmt.Fprintf(b, "\t%v -> %v [label=%v, style=%v];\n", dotQuote(v.From), dotQuote(v.To), dotQuote(label), style[v.Type])
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// Mermaid encode the graph to Mermaid flowchart, nodes are named by their index
+func (g *Graph) Mermaid() string {
+	shape := map[EntryType][2]string{UserEntry: {"([", "])"}, RoleEntry: {"[", "]"}, ObjectEntry: {"[/", "/]"}}
+	arrow := map[GraphEdgeType]string{AssignEdge: "-->", InheritEdge: "-.->", ContainEdge: "-.->", PolicyEdge: "==>"}
+
+	b := &strings.Builder{}
+	b.WriteString("flowchart LR\n")
+	name := map[string]string{}
+	for i, v := range g.Nodes {
+		name[v.ID] = fmt.Sprintf("n%v", i)
+		s := shape[v.Type]
+		fmt.Fprintf(b, "\t%v%v\"%v\"%v\n", name[v.ID], s[0], mermaidEscape(v.Label), s[1])
+	}
+	for _, v := range g.Edges {
+		label := v.Label
+		if label == "" {
+			label = string(v.Type)
+		}
+		fmt.Fprintf(b, "\t%v %v|\"%v\"| %v\n", name[v.From], arrow[v.Type], mermaidEscape(label), name[v.To])
+	}
+	return b.String()
+}
+
+// graphLabel the entry's code and the values of the keys in its JSON metadata, one per line
+func graphLabel(one entry, keys []string) string {
+	lines := []string{one.Encode()}
+	if len(keys) == 0 {
+		return lines[0]
+	}
+
+	m := map[string]interface{}{}
+	if data, err := json.Marshal(one); err == nil {
+		_ = json.Unmarshal(data, &m)
+	}
+	for _, k := range keys {
+		if v, ok := m[k]; ok && v != nil {
+			lines = append(lines, fmt.Sprint(v))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
+
+func mermaidEscape(s string) string {
+	s = strings.ReplaceAll(s, `"`, "#quot;")
+	return strings.ReplaceAll(s, "\n", "<br/>")
+}
+
+// getDescendantsID get the id and its descendants' id in the tree of child to parent
+func getDescendantsID(tree map[uint64]uint64, id uint64) []uint64 {
+	children := map[uint64][]uint64{}
+	for k, v := range tree {
+		children[v] = append(children[v], k)
+	}
+
+	out := []uint64{id}
+	seen := map[uint64]bool{id: true}
+	for i := 0; i < len(out); i++ {
+		for _, v := range children[out[i]] {
+			if !seen[v] {
+				seen[v] = true
+				out = append(out, v)
+			}
+		}
+	}
+	return out
+}
+
+// intersectID keep the id of m which are in n
+func intersectID(m map[uint64]bool, n map[uint64]bool) {
+	for k := range m {
+		if !n[k] {
+			delete(m, k)
+		}
+	}
+}
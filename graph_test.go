@@ -0,0 +1,79 @@
+package caskin_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+type graphExecutor interface {
+	ExportGraph(caskin.Domain, *caskin.GraphOption) (*caskin.Graph, error)
+}
+
+// newReadableStage the stage whose member and root are under root, admin and data are under data which is not under root,
+// then user 4 of member could read member and root only
+func newReadableStage(t *testing.T) *stage {
+	s := newStage(t, nil)
+	s.e.RemoveNamedGroupingPolicy("g2", "object_2", "object_1", s.domain.Encode())
+	for object, entries := range map[string][]interface{}{
+		"object_1": {&example.Role{ID: 2}, &example.Object{ID: 1}},
+		"object_2": {&example.Role{ID: 1}, &example.Object{ID: 2}},
+	} {
+		for _, v := range entries {
+			if err := s.db.Model(v).Update("object", object).Error; err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	s.assign(t, 3, 1)
+	s.assign(t, 4, 2)
+	return s
+}
+
+func graphNodes(g *caskin.Graph) map[string]bool {
+	nodes := map[string]bool{}
+	for _, v := range g.Nodes {
+		nodes[v.ID] = true
+	}
+	return nodes
+}
+
+func TestExportGraphReadable(t *testing.T) {
+	s := newReadableStage(t)
+	g, err := s.executor(s.as(4)).(graphExecutor).ExportGraph(&example.Domain{ID: s.domain.ID}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	nodes := graphNodes(g)
+	if !nodes["user_4"] || !nodes["role_2"] || !nodes["object_1"] || nodes["role_1"] || nodes["object_2"] {
+		t.Fatal(nodes)
+	}
+
+	// the option's object must be readable too
+	var perr *caskin.PermissionError
+	option := &caskin.GraphOption{Object: &example.Object{ID: 2}}
+	if _, err := s.executor(s.as(4)).(graphExecutor).ExportGraph(&example.Domain{ID: s.domain.ID}, option); !errors.As(err, &perr) {
+		t.Fatal(err)
+	}
+
+	g, err = s.executor(s.as(1)).(graphExecutor).ExportGraph(&example.Domain{ID: s.domain.ID}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if nodes := graphNodes(g); !nodes["user_3"] || !nodes["role_1"] || !nodes["object_2"] {
+		t.Fatal(nodes)
+	}
+}
+
+func TestExportGraphCrossDomain(t *testing.T) {
+	s, other := newCrossDomainStage(t)
+	s.assign(t, 3, 1)
+	if _, err := s.executor(s.as(3)).(graphExecutor).ExportGraph(&example.Domain{ID: other.ID}, nil); !errors.Is(err, caskin.ErrCrossDomain) {
+		t.Fatal(err)
+	}
+	if _, err := s.executor(s.as(1)).(graphExecutor).ExportGraph(&example.Domain{ID: other.ID}, nil); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,168 @@
+package caskin
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+type MatrixMark string
+
+const (
+	// the role has the policy of the action on the object
+	ExplicitMark MatrixMark = "explicit"
+	// the role gets the action by its child role's policy or the object's ancestor's policy
+	InheritedMark MatrixMark = "inherited"
+)
+
+// PermissionMatrix the roles' actions on the objects of a domain
+type PermissionMatrix struct {
+	Domain  Domain
+	Roles   []Role
+	Objects []Object
+	Actions []Action
+	// Cells[i][j] the actions of Roles[i] on Objects[j]
+	Cells [][]map[Action]MatrixMark
+}
+
+// newPermissionMatrix build the matrix of the spec's roles and objects
+// 1. parent role inherits child role's policies, so the role gets the policies of itself and its descendants
+// 2. child object inherits parent object's policies, so the object gets the policies of itself and its ancestors
+// 3. the cell is explicit if the role has the policy on the object, otherwise inherited
+func newPermissionMatrix(spec *DomainSpec, domain Domain) *PermissionMatrix {
+	m := &PermissionMatrix{
+		Domain:  domain,
+		Roles:   spec.Roles,
+		Objects: spec.Objects,
+	}
+
+	roleTree, objectTree := getTree(spec.Roles), getTree(spec.Objects)
+	// role's id to its policies' actions by object's id
+	policies := map[uint64]map[uint64][]Action{}
+	actions := map[Action]bool{}
+	for _, p := range spec.Policies {
+		if _, ok := policies[p.Role.GetID()]; !ok {
+			policies[p.Role.GetID()] = map[uint64][]Action{}
+		}
+		policies[p.Role.GetID()][p.Object.GetID()] = append(policies[p.Role.GetID()][p.Object.GetID()], p.Action)
+		if !actions[p.Action] {
+			actions[p.Action] = true
+			m.Actions = append(m.Actions, p.Action)
+		}
+	}
+	sort.Slice(m.Actions, func(i, j int) bool {
+		return m.Actions[i] < m.Actions[j]
+	})
+
+	for _, r := range spec.Roles {
+		roles := getDescendantsID(roleTree, r.GetID())
+		row := make([]map[Action]MatrixMark, len(spec.Objects))
+		for j, o := range spec.Objects {
+			cell := map[Action]MatrixMark{}
+			for _, rid := range roles {
+				for _, oid := range getAncestorsID(objectTree, o.GetID()) {
+					for _, action := range policies[rid][oid] {
+						if rid == r.GetID() && oid == o.GetID() {
+							cell[action] = ExplicitMark
+						} else if _, ok := cell[action]; !ok {
+							cell[action] = InheritedMark
+						}
+					}
+				}
+			}
+			row[j] = cell
+		}
+		m.Cells = append(m.Cells, row)
+	}
+
+	return m
+}
+
+// CSV encode the matrix to CSV, the first row is objects' code and the first column is roles' code,
+// inherited action is marked by "*" in the cell
+func (m *PermissionMatrix) CSV() ([]byte, error) {
+	b := &bytes.Buffer{}
+	w := csv.NewWriter(b)
+	for _, v := range m.rows() {
+		if err := w.Write(v); err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	return b.Bytes(), w.Error()
+}
+
+// Markdown encode the matrix to Markdown table, inherited action is marked by "*" in the cell
+func (m *PermissionMatrix) Markdown() string {
+	b := &strings.Builder{}
+	for i, v := range m.rows() {
+		for j := range v {
+			v[j] = strings.ReplaceAll(v[j], "|", `\|`)
+		}
+		fmt.Fprintf(b, "| %v |\n", strings.Join(v, " | "))
+		if i == 0 {
+			fmt.Fprintf(b, "|%v\n", strings.Repeat(" --- |", len(v)))
+		}
+	}
+	b.WriteString("\n\\* inherited from child role or parent object\n")
+	return b.String()
+}
+
+// HTML encode the matrix to HTML table, the action is in a span with class "explicit" or "inherited"
+func (m *PermissionMatrix) HTML() string {
+	b := &strings.Builder{}
+	b.WriteString("<table class=\"caskin-matrix\">\n<thead>\n<tr><th>role</th>")
+	for _, v := range m.Objects {
+		fmt.Fprintf(b, "<th>%v</th>", html.EscapeString(v.Encode()))
+	}
+	b.WriteString("</tr>\n</thead>\n<tbody>\n")
+	for i, r := range m.Roles {
+		fmt.Fprintf(b, "<tr><th>%v</th>", html.EscapeString(r.Encode()))
+		for _, cell := range m.Cells[i] {
+			var spans []string
+			for _, action := range m.Actions {
+				if mark, ok := cell[action]; ok {
+					spans = append(spans, fmt.Sprintf("<span class=\"%v\">%v</span>", mark, html.EscapeString(string(action))))
+				}
+			}
+			fmt.Fprintf(b, "<td>%v</td>", strings.Join(spans, " "))
+		}
+		b.WriteString("</tr>\n")
+	}
+	b.WriteString("</tbody>\n</table>\n")
+	return b.String()
+}
+
+// rows the header of objects' code and a row of each role with its cells' text
+func (m *PermissionMatrix) rows() [][]string {
+	header := []string{"role"}
+	for _, v := range m.Objects {
+		header = append(header, v.Encode())
+	}
+	rows := [][]string{header}
+	for i, r := range m.Roles {
+		row := []string{r.Encode()}
+		for _, cell := range m.Cells[i] {
+			row = append(row, m.cellText(cell))
+		}
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// cellText such as "read write*", the actions are in order of m.Actions
+func (m *PermissionMatrix) cellText(cell map[Action]MatrixMark) string {
+	var text []string
+	for _, action := range m.Actions {
+		switch cell[action] {
+		case ExplicitMark:
+			text = append(text, string(action))
+		case InheritedMark:
+			text = append(text, string(action)+"*")
+		}
+	}
+	return strings.Join(text, " ")
+}
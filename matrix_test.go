@@ -0,0 +1,80 @@
+package caskin_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+type matrixExecutor interface {
+	GetPermissionMatrix(caskin.Domain) (*caskin.PermissionMatrix, error)
+}
+
+// matrixCell get the cell of the role and object by their codes
+func matrixCell(t *testing.T, m *caskin.PermissionMatrix, role, object string) map[caskin.Action]caskin.MatrixMark {
+	for i, r := range m.Roles {
+		for j, o := range m.Objects {
+			if r.Encode() == role && o.Encode() == object {
+				return m.Cells[i][j]
+			}
+		}
+	}
+	t.Fatalf("there is no cell of %v and %v", role, object)
+	return nil
+}
+
+func TestPermissionMatrix(t *testing.T) {
+	s := newStage(t, nil)
+	m, err := s.executor(s.as(1)).(matrixExecutor).GetPermissionMatrix(&example.Domain{ID: s.domain.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Roles) != 2 || len(m.Objects) != 2 || len(m.Actions) != 2 {
+		t.Fatal(m.Roles, m.Objects, m.Actions)
+	}
+
+	// admin inherits member, and data is under root
+	for _, v := range []struct {
+		role, object string
+		cell         map[caskin.Action]caskin.MatrixMark
+	}{
+		{"role_1", "object_1", map[caskin.Action]caskin.MatrixMark{caskin.Write: caskin.ExplicitMark, caskin.Read: caskin.InheritedMark}},
+		{"role_1", "object_2", map[caskin.Action]caskin.MatrixMark{caskin.Write: caskin.InheritedMark, caskin.Read: caskin.InheritedMark}},
+		{"role_2", "object_1", map[caskin.Action]caskin.MatrixMark{caskin.Read: caskin.ExplicitMark}},
+		{"role_2", "object_2", map[caskin.Action]caskin.MatrixMark{caskin.Read: caskin.InheritedMark}},
+	} {
+		cell := matrixCell(t, m, v.role, v.object)
+		if len(cell) != len(v.cell) {
+			t.Fatal(v.role, v.object, cell)
+		}
+		for action, mark := range v.cell {
+			if cell[action] != mark {
+				t.Fatal(v.role, v.object, cell)
+			}
+		}
+	}
+}
+
+func TestPermissionMatrixReadable(t *testing.T) {
+	s := newReadableStage(t)
+	m, err := s.executor(s.as(4)).(matrixExecutor).GetPermissionMatrix(&example.Domain{ID: s.domain.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Roles) != 1 || len(m.Objects) != 1 || matrixCell(t, m, "role_2", "object_1")[caskin.Read] != caskin.ExplicitMark {
+		t.Fatal(m.Roles, m.Objects, m.Cells)
+	}
+}
+
+func TestPermissionMatrixCrossDomain(t *testing.T) {
+	s, other := newCrossDomainStage(t)
+	s.assign(t, 3, 1)
+	if _, err := s.executor(s.as(3)).(matrixExecutor).GetPermissionMatrix(&example.Domain{ID: other.ID}); !errors.Is(err, caskin.ErrCrossDomain) {
+		t.Fatal(err)
+	}
+	if _, err := s.executor(s.as(1)).(matrixExecutor).GetPermissionMatrix(&example.Domain{ID: other.ID}); err != nil {
+		t.Fatal(err)
+	}
+}
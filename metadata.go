@@ -1,5 +1,7 @@
 package caskin
 
+import "time"
+
 type MetaDB interface {
 	// User API
 	TakeUser(User) error
@@ -33,3 +35,15 @@ type MetaDB interface {
 	GetAllDomain() ([]Domain, error)
 	DeleteDomainByID(uint64) error
 }
+
+// TrashMetaDB the optional API of MetaDB for soft deleted entries,
+// it is required to list, purge and recover soft deleted entries, and to repair consistency
+type TrashMetaDB interface {
+	// soft deleted ones before the time, and hard delete
+	GetDeletedRoleInDomain(Domain, time.Time) ([]Role, error)
+	PurgeRoleByID(uint64) error
+	GetDeletedObjectInDomain(Domain, time.Time) ([]Object, error)
+	PurgeObjectByID(uint64) error
+	GetAllDeletedDomain(time.Time) ([]Domain, error)
+	PurgeDomainByID(uint64) error
+}
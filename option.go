@@ -1,6 +1,10 @@
 package caskin
 
-import "math"
+import (
+	"fmt"
+	"math"
+	"time"
+)
 
 var (
 	DefaultSuperadminRoleID   uint64 = math.MaxInt32
@@ -10,14 +14,36 @@ var (
 	DefaultSuperadminDomainName = "superadmin_domain"
 	// default
 	DefaultSeparator = ","
+	// JSON fields to identify template's role and object when Option.TemplateKeyFields is empty
+	DefaultTemplateKeyFields = []string{"name"}
+	// JSON fields not compared when Option.CompareFields is empty
+	DefaultCompareIgnoredFields = []string{"created_at", "updated_at", "deleted_at", "CreatedAt", "UpdatedAt", "DeletedAt"}
 )
 
 type Option struct {
 	// option of superadmin
 	SuperAdminOption *SuperAdminOption `json:"super_admin_option"`
 
+	// option of domain admin
+	DomainAdminOption *DomainAdminOption `json:"domain_admin_option"`
+
 	// create new domain's function
 	DomainCreator DomainCreator
+
+	// versioned DomainCreator, the latest one is used instead of DomainCreator if there is any
+	DomainTemplates []*DomainTemplate
+
+	// JSON fields to identify template's role and object in domain when migrating and checking drift, such as ["name"].
+	// default is empty, then they are identified by DefaultTemplateKeyFields, or by all their fields without them
+	TemplateKeyFields []string `json:"template_key_fields"`
+
+	// JSON fields compared to find the modified role and object of DomainSpec and template, such as ["name", "object"],
+	// zero value is compared too, so a field could be cleared by the spec.
+	// default is empty, then all JSON fields are compared except id, parent, domain and DefaultCompareIgnoredFields
+	CompareFields []string `json:"compare_fields"`
+
+	// option of tombstone
+	TombstoneOption *TombstoneOption `json:"tombstone_option"`
 }
 
 type SuperAdminOption struct {
@@ -30,10 +56,39 @@ type SuperAdminOption struct {
 	Role               func() Role
 	// provide superadmin Domain
 	Domain             func() Domain
+	// user id to be seeded as superadmin when caskin is built
+	InitialUsers       []uint64 `json:"initial_users"`
+}
+
+type DomainAdminOption struct {
+	// default is false
+	Enable bool `json:"enable"`
+	// pick out domain admin role from DomainCreator's roles.
+	// it is required if Enable is true
+	Role func([]Role) Role
+}
+
+type TombstoneOption struct {
+	// archive the g, g2 and p removed by deleting an entry.
+	// default is nil, then they are not archived.
+	// NewMemoryTombstoneStore is process local, implement TombstoneStore to persist them
+	Store TombstoneStore
+	// how long a tombstone is kept before PurgeTombstones.
+	// it is applied only when PurgeTombstones is called, nothing expires them by itself.
+	// default is 0, then tombstones are kept forever
+	Retention time.Duration `json:"retention"`
+	// restore the tombstone's rules when recovering the entry.
+	// default is false
+	RestoreOnRecover bool `json:"restore_on_recover"`
 }
 
 type DomainCreator func(Domain) ([]Role, []Object, []*Policy)
 
+type DomainTemplate struct {
+	Version uint64
+	Creator DomainCreator
+}
+
 func (o *Option) IsEnableSuperAdmin() bool {
 	return o.SuperAdminOption != nil && o.SuperAdminOption.Enable
 }
@@ -61,3 +116,74 @@ func (o *Option) GetSuperAdminDomain() Domain {
 
 	return &sampleSuperAdminDomain{}
 }
+
+func (o *Option) IsEnableDomainAdmin() bool {
+	return o.DomainAdminOption != nil && o.DomainAdminOption.Enable
+}
+
+func (o *Option) GetDomainAdminRole(roles []Role) Role {
+	if !o.IsEnableDomainAdmin() || len(roles) == 0 {
+		return nil
+	}
+
+	if o.DomainAdminOption.Role == nil {
+		return nil
+	}
+
+	return o.DomainAdminOption.Role(roles)
+}
+
+// validate the option
+// 1. domain admin role's picker is required if domain admin is enabled
+func (o *Option) validate() error {
+	if o.IsEnableDomainAdmin() && o.DomainAdminOption.Role == nil {
+		return fmt.Errorf("%w: domain admin role should be provided when domain admin is enabled", ErrInvalidDomainAdmin)
+	}
+
+	return nil
+}
+
+// GetLatestDomainTemplate get the latest DomainTemplate,
+// it is DomainCreator as version 0 if there is no DomainTemplates
+func (o *Option) GetLatestDomainTemplate() *DomainTemplate {
+	if len(o.DomainTemplates) == 0 {
+		return &DomainTemplate{Creator: o.DomainCreator}
+	}
+
+	latest := o.DomainTemplates[0]
+	for _, v := range o.DomainTemplates {
+		if v.Version > latest.Version {
+			latest = v
+		}
+	}
+
+	return latest
+}
+
+// GetDomainTemplate get the DomainTemplate of the version, it is nil if there is no such version,
+// version 0 is DomainCreator unless there is a DomainTemplate of version 0
+func (o *Option) GetDomainTemplate(version uint64) *DomainTemplate {
+	for _, v := range o.DomainTemplates {
+		if v.Version == version {
+			return v
+		}
+	}
+
+	if version == 0 {
+		return &DomainTemplate{Creator: o.DomainCreator}
+	}
+
+	return nil
+}
+
+func (o *Option) GetTombstoneStore() TombstoneStore {
+	if o.TombstoneOption == nil {
+		return nil
+	}
+
+	return o.TombstoneOption.Store
+}
+
+func (o *Option) IsRestoreOnRecover() bool {
+	return o.GetTombstoneStore() != nil && o.TombstoneOption.RestoreOnRecover
+}
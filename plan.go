@@ -0,0 +1,305 @@
+package caskin
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type PlanOperation string
+
+const (
+	PlanCreate PlanOperation = "create"
+	PlanUpdate PlanOperation = "update"
+	PlanDelete PlanOperation = "delete"
+)
+
+// DomainPlan the steps to make a domain the same as a DomainSpec
+type DomainPlan struct {
+	Roles   []*RoleStep
+	Objects []*ObjectStep
+	// role's g
+	RoleParents []*RoleParentStep
+	// object's g2
+	ObjectParents []*ObjectParentStep
+	Policies      []*PolicyStep
+}
+
+type RoleStep struct {
+	Operation PlanOperation
+	Role      Role
+	// if the step is applied to the domain
+	Applied bool
+}
+
+type ObjectStep struct {
+	Operation PlanOperation
+	Object    Object
+	// if the step is applied to the domain
+	Applied bool
+}
+
+type RoleParentStep struct {
+	Operation PlanOperation
+	Role      Role
+	Parent    Role
+	// if the step is applied to the domain
+	Applied bool
+}
+
+type ObjectParentStep struct {
+	Operation PlanOperation
+	Object    Object
+	Parent    Object
+	// if the step is applied to the domain
+	Applied bool
+}
+
+type PolicyStep struct {
+	Operation PlanOperation
+	Policy    *Policy
+	// if the step is applied to the domain
+	Applied bool
+}
+
+// IsEmpty if there is nothing to do
+func (p *DomainPlan) IsEmpty() bool {
+	return len(p.Roles)+len(p.Objects)+len(p.RoleParents)+len(p.ObjectParents)+len(p.Policies) == 0
+}
+
+// String one step per line as dry run output
+func (p *DomainPlan) String() string {
+	var lines []string
+	for _, v := range p.Roles {
+		lines = append(lines, fmt.Sprintf("%v role %v", v.Operation, v.Role.Encode()))
+	}
+	for _, v := range p.Objects {
+		lines = append(lines, fmt.Sprintf("%v object %v", v.Operation, v.Object.Encode()))
+	}
+	for _, v := range p.RoleParents {
+		lines = append(lines, fmt.Sprintf("%v role parent %v -> %v", v.Operation, v.Role.Encode(), v.Parent.Encode()))
+	}
+	for _, v := range p.ObjectParents {
+		lines = append(lines, fmt.Sprintf("%v object parent %v -> %v", v.Operation, v.Object.Encode(), v.Parent.Encode()))
+	}
+	for _, v := range p.Policies {
+		lines = append(lines, fmt.Sprintf("%v policy %v %v %v",
+			v.Operation, v.Policy.Role.Encode(), v.Policy.Object.Encode(), v.Policy.Action))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+type parentEdge struct {
+	id       uint64
+	parentID uint64
+}
+
+type policyKey struct {
+	role   uint64
+	object uint64
+	action Action
+}
+
+// planDomain diff current and target spec of a domain to get the plan,
+// the role or object is updated if any of the fields is modified
+func planDomain(current, target *DomainSpec, factory EntryFactory, fields []string) *DomainPlan {
+	plan := &DomainPlan{}
+
+	// roles
+	currentRoles, targetRoles := getIDMap(current.Roles), getIDMap(target.Roles)
+	add, remove := Diff(idInterfaces(current.Roles), idInterfaces(target.Roles))
+	for _, v := range add {
+		plan.Roles = append(plan.Roles, &RoleStep{Operation: PlanCreate, Role: targetRoles[v.(uint64)].(Role)})
+	}
+	for _, v := range target.Roles {
+		if c, ok := currentRoles[v.GetID()]; ok && isEntryModified(c.(Role), v, fields) {
+			plan.Roles = append(plan.Roles, &RoleStep{Operation: PlanUpdate, Role: v})
+		}
+	}
+	for _, v := range remove {
+		plan.Roles = append(plan.Roles, &RoleStep{Operation: PlanDelete, Role: currentRoles[v.(uint64)].(Role)})
+	}
+
+	// objects
+	currentObjects, targetObjects := getIDMap(current.Objects), getIDMap(target.Objects)
+	add, remove = Diff(idInterfaces(current.Objects), idInterfaces(target.Objects))
+	for _, v := range add {
+		plan.Objects = append(plan.Objects, &ObjectStep{Operation: PlanCreate, Object: targetObjects[v.(uint64)].(Object)})
+	}
+	for _, v := range target.Objects {
+		if c, ok := currentObjects[v.GetID()]; ok && isEntryModified(c.(Object), v, fields) {
+			plan.Objects = append(plan.Objects, &ObjectStep{Operation: PlanUpdate, Object: v})
+		}
+	}
+	for _, v := range remove {
+		plan.Objects = append(plan.Objects, &ObjectStep{Operation: PlanDelete, Object: currentObjects[v.(uint64)].(Object)})
+	}
+
+	// role's g
+	add, remove = Diff(parentEdges(current.Roles), parentEdges(target.Roles))
+	for _, v := range add {
+		plan.RoleParents = append(plan.RoleParents, newRoleParentStep(PlanCreate, v, factory))
+	}
+	for _, v := range remove {
+		plan.RoleParents = append(plan.RoleParents, newRoleParentStep(PlanDelete, v, factory))
+	}
+
+	// object's g2
+	add, remove = Diff(parentEdges(current.Objects), parentEdges(target.Objects))
+	for _, v := range add {
+		plan.ObjectParents = append(plan.ObjectParents, newObjectParentStep(PlanCreate, v, factory))
+	}
+	for _, v := range remove {
+		plan.ObjectParents = append(plan.ObjectParents, newObjectParentStep(PlanDelete, v, factory))
+	}
+
+	// policy's p
+	currentPolicies, targetPolicies := policyKeyMap(current.Policies), policyKeyMap(target.Policies)
+	add, remove = Diff(policyKeys(current.Policies), policyKeys(target.Policies))
+	for _, v := range add {
+		plan.Policies = append(plan.Policies, &PolicyStep{Operation: PlanCreate, Policy: targetPolicies[v.(policyKey)]})
+	}
+	for _, v := range remove {
+		plan.Policies = append(plan.Policies, &PolicyStep{Operation: PlanDelete, Policy: currentPolicies[v.(policyKey)]})
+	}
+
+	return plan
+}
+
+// isEntryModified compare current and target entry's JSON fields in the mask except parent which is diffed as tree,
+// the field omitted by JSON is compared as null.
+// if the mask is empty, all JSON fields are compared except id, parent, domain and DefaultCompareIgnoredFields
+func isEntryModified(current, target parentEntry, fields []string) bool {
+	id, pid := target.GetID(), target.GetParentID()
+	target.SetID(current.GetID())
+	target.SetParentID(current.GetParentID())
+	defer target.SetID(id)
+	defer target.SetParentID(pid)
+	c1, ok1 := current.(inDomain)
+	t1, ok2 := target.(inDomain)
+	if ok1 && ok2 {
+		did := t1.GetDomainID()
+		t1.SetDomainID(c1.GetDomainID())
+		defer t1.SetDomainID(did)
+	}
+
+	c, t := entryToMap(current), entryToMap(target)
+	if len(fields) == 0 {
+		fields = allComparedFields(c, t)
+	}
+	for _, k := range fields {
+		if !reflect.DeepEqual(c[k], t[k]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// allComparedFields get all JSON fields of the entries except DefaultCompareIgnoredFields
+func allComparedFields(entries ...map[string]interface{}) []string {
+	ignored := map[string]bool{}
+	for _, k := range DefaultCompareIgnoredFields {
+		ignored[k] = true
+	}
+
+	var fields []string
+	for _, m := range entries {
+		for k := range m {
+			if !ignored[k] {
+				ignored[k] = true
+				fields = append(fields, k)
+			}
+		}
+	}
+	return fields
+}
+
+func entryToMap(one entry) map[string]interface{} {
+	m := map[string]interface{}{}
+	data, err := json.Marshal(one)
+	if err != nil {
+		return m
+	}
+	_ = json.Unmarshal(data, &m)
+	return m
+}
+
+func idInterfaces(source interface{}) []interface{} {
+	var out []interface{}
+	for _, v := range getIDList(source) {
+		out = append(out, v)
+	}
+	return out
+}
+
+func parentEdges(source interface{}) []interface{} {
+	var edges []parentEdge
+	for id, pid := range getTree(source) {
+		edges = append(edges, parentEdge{id: id, parentID: pid})
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		return edges[i].id < edges[j].id
+	})
+
+	var out []interface{}
+	for _, v := range edges {
+		out = append(out, v)
+	}
+	return out
+}
+
+func newRoleParentStep(operation PlanOperation, v interface{}, factory EntryFactory) *RoleParentStep {
+	edge := v.(parentEdge)
+	role, parent := factory.NewRole(), factory.NewRole()
+	role.SetID(edge.id)
+	role.SetParentID(edge.parentID)
+	parent.SetID(edge.parentID)
+	return &RoleParentStep{Operation: operation, Role: role, Parent: parent}
+}
+
+func newObjectParentStep(operation PlanOperation, v interface{}, factory EntryFactory) *ObjectParentStep {
+	edge := v.(parentEdge)
+	object, parent := factory.NewObject(), factory.NewObject()
+	object.SetID(edge.id)
+	object.SetParentID(edge.parentID)
+	parent.SetID(edge.parentID)
+	return &ObjectParentStep{Operation: operation, Object: object, Parent: parent}
+}
+
+func policyKeys(policies []*Policy) []interface{} {
+	var keys []policyKey
+	for k := range policyKeyMap(policies) {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].role != keys[j].role {
+			return keys[i].role < keys[j].role
+		}
+		if keys[i].object != keys[j].object {
+			return keys[i].object < keys[j].object
+		}
+		return keys[i].action < keys[j].action
+	})
+
+	var out []interface{}
+	for _, v := range keys {
+		out = append(out, v)
+	}
+	return out
+}
+
+func policyKeyMap(policies []*Policy) map[policyKey]*Policy {
+	m := map[policyKey]*Policy{}
+	for _, v := range policies {
+		m[toPolicyKey(v)] = v
+	}
+	return m
+}
+
+func toPolicyKey(policy *Policy) policyKey {
+	return policyKey{role: policy.Role.GetID(), object: policy.Object.GetID(), action: policy.Action}
+}
@@ -7,6 +7,9 @@ func (s *sampleSuperadminRole) GetID() uint64 {
 	return DefaultSuperadminRoleID
 }
 
+func (s *sampleSuperadminRole) SetID(uint64) {
+}
+
 func (s *sampleSuperadminRole) Encode() string {
 	return SuperadminRole
 }
@@ -33,6 +36,13 @@ func (s *sampleSuperadminRole) GetParentID() uint64 {
 func (s *sampleSuperadminRole) SetParentID(uint64) {
 }
 
+func (s *sampleSuperadminRole) GetDomainID() uint64 {
+	return DefaultSuperadminDomainID
+}
+
+func (s *sampleSuperadminRole) SetDomainID(uint64) {
+}
+
 type sampleSuperAdminDomain struct {
 }
 
@@ -40,6 +50,9 @@ func (s *sampleSuperAdminDomain) GetID() uint64 {
 	return DefaultSuperadminDomainID
 }
 
+func (s *sampleSuperAdminDomain) SetID(uint64) {
+}
+
 func (s *sampleSuperAdminDomain) Encode() string {
 	return SuperadminDomain
 }
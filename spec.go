@@ -0,0 +1,148 @@
+package caskin
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+// DomainSpec the declarative configuration of a domain's
+// roles, objects, role's tree, object's tree and policies.
+// role and object's parent is set by SetParentID, and policy refers them by id.
+type DomainSpec struct {
+	Roles    []Role
+	Objects  []Object
+	Policies []*Policy
+}
+
+type domainSpecJSON struct {
+	Roles    []json.RawMessage `json:"roles"`
+	Objects  []json.RawMessage `json:"objects"`
+	Policies []*policyJSON     `json:"policies"`
+}
+
+type policyJSON struct {
+	Role   uint64 `json:"role"`
+	Object uint64 `json:"object"`
+	Action Action `json:"action"`
+}
+
+// DecodeDomainSpec decode YAML or JSON data to DomainSpec,
+// roles and objects are unmarshalled into the EntryFactory's entries
+func DecodeDomainSpec(data []byte, factory EntryFactory) (*DomainSpec, error) {
+	data, err := yaml.YAMLToJSON(data)
+	if err != nil {
+		return nil, err
+	}
+
+	raw := &domainSpecJSON{}
+	if err := json.Unmarshal(data, raw); err != nil {
+		return nil, err
+	}
+
+	return raw.toSpec(factory)
+}
+
+// EncodeDomainSpec encode DomainSpec to JSON data, use sigs.k8s.io/yaml's JSONToYAML to get YAML data
+func EncodeDomainSpec(spec *DomainSpec) ([]byte, error) {
+	raw, err := newDomainSpecJSON(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.MarshalIndent(raw, "", "  ")
+}
+
+func (raw *domainSpecJSON) toSpec(factory EntryFactory) (*DomainSpec, error) {
+	spec := &DomainSpec{}
+	for _, v := range raw.Roles {
+		role := factory.NewRole()
+		if err := json.Unmarshal(v, role); err != nil {
+			return nil, err
+		}
+		spec.Roles = append(spec.Roles, role)
+	}
+	for _, v := range raw.Objects {
+		object := factory.NewObject()
+		if err := json.Unmarshal(v, object); err != nil {
+			return nil, err
+		}
+		spec.Objects = append(spec.Objects, object)
+	}
+	for _, v := range raw.Policies {
+		role := factory.NewRole()
+		role.SetID(v.Role)
+		object := factory.NewObject()
+		object.SetID(v.Object)
+		spec.Policies = append(spec.Policies, &Policy{Role: role, Object: object, Action: v.Action})
+	}
+
+	return spec, nil
+}
+
+func newDomainSpecJSON(spec *DomainSpec) (*domainSpecJSON, error) {
+	raw := &domainSpecJSON{}
+	for _, v := range spec.Roles {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		raw.Roles = append(raw.Roles, data)
+	}
+	for _, v := range spec.Objects {
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		raw.Objects = append(raw.Objects, data)
+	}
+	for _, v := range spec.Policies {
+		raw.Policies = append(raw.Policies, &policyJSON{
+			Role:   v.Role.GetID(),
+			Object: v.Object.GetID(),
+			Action: v.Action,
+		})
+	}
+
+	return raw, nil
+}
+
+// validate check spec's entries have id,
+// and policies, parents refer to roles and objects in the spec
+func (s *DomainSpec) validate() error {
+	roles := getIDMap(s.Roles)
+	objects := getIDMap(s.Objects)
+	if len(roles) != len(s.Roles) || len(objects) != len(s.Objects) {
+		return fmt.Errorf("%w: duplicated role or object id in domain spec", ErrInvalidDomainSpec)
+	}
+
+	for _, v := range s.Roles {
+		if err := isValid(v); err != nil {
+			return fmt.Errorf("%w: role %v", ErrInvalidDomainSpec, err)
+		}
+		if _, ok := roles[v.GetParentID()]; v.GetParentID() != 0 && !ok {
+			return fmt.Errorf("%w: role %v's parent %v is not in spec", ErrInvalidDomainSpec, v.GetID(), v.GetParentID())
+		}
+	}
+
+	for _, v := range s.Objects {
+		if err := isValid(v); err != nil {
+			return fmt.Errorf("%w: object %v", ErrInvalidDomainSpec, err)
+		}
+		if _, ok := objects[v.GetParentID()]; v.GetParentID() != 0 && !ok {
+			return fmt.Errorf("%w: object %v's parent %v is not in spec", ErrInvalidDomainSpec, v.GetID(), v.GetParentID())
+		}
+	}
+
+	for _, v := range s.Policies {
+		_, ok1 := roles[v.Role.GetID()]
+		_, ok2 := objects[v.Object.GetID()]
+		if !ok1 || !ok2 {
+			return fmt.Errorf("%w: policy %v %v %v refers to role or object not in spec",
+				ErrInvalidDomainSpec, v.Role.GetID(), v.Object.GetID(), v.Action)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package caskin_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+type specExecutor interface {
+	ApplyDomainSpec(*caskin.DomainSpec, bool) (*caskin.DomainPlan, error)
+}
+
+// stageSpec rename member to staff, clear admin's object, add guest reading data instead of member reading root
+const stageSpec = `
+roles:
+- {id: 1, name: admin}
+- {id: 2, name: staff, parent_id: 1}
+- {id: 3, name: guest}
+objects:
+- {id: 1, name: root, type: default}
+- {id: 2, name: data, type: default, parent_id: 1}
+policies:
+- {role: 1, object: 1, action: write}
+- {role: 3, object: 2, action: read}
+`
+
+func TestApplyDomainSpec(t *testing.T) {
+	s := newStage(t, &caskin.Option{CompareFields: []string{"name", "object"}})
+	if err := s.db.Model(&example.Role{ID: 1}).Update("object", "object_1").Error; err != nil {
+		t.Fatal(err)
+	}
+
+	spec, err := caskin.DecodeDomainSpec([]byte(stageSpec), example.NewEntryFactory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := s.executor(s.as(1)).(specExecutor).ApplyDomainSpec(spec, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"create role role_3",
+		"update role role_1",
+		"update role role_2",
+		"create policy role_3 object_2 read",
+		"delete policy role_2 object_1 read",
+	}
+	if plan.String() != strings.Join(want, "\n") {
+		t.Fatal(plan.String())
+	}
+	if role := (&example.Role{ID: 2}); s.mdb.TakeRole(role) != nil || role.Name != "member" {
+		t.Fatal("dry run changes the domain")
+	}
+
+	if _, err := s.executor(s.as(1)).(specExecutor).ApplyDomainSpec(spec, false); err != nil {
+		t.Fatal(err)
+	}
+	if role := (&example.Role{ID: 2}); s.mdb.TakeRole(role) != nil || role.Name != "staff" {
+		t.Fatal(role)
+	}
+	if role := (&example.Role{ID: 3}); s.mdb.TakeRole(role) != nil || role.DomainID != s.domain.ID {
+		t.Fatal(role)
+	}
+	if ok, _ := s.e.Enforce("role_3", s.domain.Encode(), "object_2", string(caskin.Read)); !ok {
+		t.Fatal("guest can't read data")
+	}
+	if ok := s.e.HasPolicy("role_2", s.domain.Encode(), "object_1", string(caskin.Read)); ok {
+		t.Fatal("member's policy is not deleted")
+	}
+}
+
+func TestApplyDomainSpecCompareFields(t *testing.T) {
+	s := newStage(t, nil)
+	spec, err := caskin.DecodeDomainSpec([]byte(stageSpec), example.NewEntryFactory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	plan, err := s.executor(s.as(1)).(specExecutor).ApplyDomainSpec(spec, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var updated []string
+	for _, v := range plan.Roles {
+		if v.Operation == caskin.PlanUpdate {
+			updated = append(updated, v.Role.Encode())
+		}
+	}
+	if strings.Join(updated, ",") != "role_2" {
+		t.Fatal("renamed role is not updated without CompareFields", updated)
+	}
+}
+
+func TestApplyDomainSpecApplied(t *testing.T) {
+	s := newStage(t, nil)
+	spec, err := caskin.DecodeDomainSpec([]byte(stageSpec), example.NewEntryFactory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// guest is named as admin, creating it conflicts after member's policy is deleted
+	spec.Roles[2].(*example.Role).Name = "admin"
+	plan, err := s.executor(s.as(1)).(specExecutor).ApplyDomainSpec(spec, false)
+	if err == nil || plan == nil {
+		t.Fatal(err)
+	}
+	for _, v := range plan.Policies {
+		if v.Operation == caskin.PlanDelete && !v.Applied {
+			t.Fatal("deleted policy is not marked as applied")
+		}
+		if v.Operation == caskin.PlanCreate && v.Applied {
+			t.Fatal("policy is marked as applied after failure")
+		}
+	}
+	for _, v := range plan.Roles {
+		if v.Operation == caskin.PlanCreate && v.Applied {
+			t.Fatal("conflicted role is marked as applied")
+		}
+	}
+}
+
+func TestApplyDomainSpecPermission(t *testing.T) {
+	s := newStage(t, nil)
+	s.assign(t, 2, 2)
+	spec, err := caskin.DecodeDomainSpec([]byte(stageSpec), example.NewEntryFactory())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.executor(s.as(2)).(specExecutor).ApplyDomainSpec(spec, true); !errors.Is(err, caskin.ErrNoWritePermission) {
+		t.Fatal(err)
+	}
+
+	spec.Policies = append(spec.Policies, &caskin.Policy{Role: &example.Role{ID: 9}, Object: &example.Object{ID: 1}, Action: caskin.Read})
+	if _, err := s.executor(s.as(1)).(specExecutor).ApplyDomainSpec(spec, true); !errors.Is(err, caskin.ErrInvalidDomainSpec) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,105 @@
+package caskin_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+	"github.com/casbin/casbin/v2"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// stage the example's entries in sqlite memory database and casbin's enforcer without adapter,
+// user 1 is superadmin, and domain 1 is created by user 1 with stageCreator
+type stage struct {
+	db     *gorm.DB
+	mdb    caskin.MetaDB
+	e      *casbin.Enforcer
+	domain *example.Domain
+	// build caskin's executor, assert it to the interface of the methods to test
+	executor func(caskin.CurrentUserProvider) interface{}
+}
+
+type provider struct {
+	user   caskin.User
+	domain caskin.Domain
+}
+
+func (p *provider) Get() (caskin.User, caskin.Domain, error) {
+	return p.user, p.domain, nil
+}
+
+// stageCreator create role 1 admin, role 2 member, object 1 root and object 2 data,
+// admin can write root and member can read root, newStage makes admin inherit member and data under root
+func stageCreator(domain caskin.Domain) ([]caskin.Role, []caskin.Object, []*caskin.Policy) {
+	root := &example.Object{Name: "root", Type: example.ObjectTypeDefault, DomainID: domain.GetID()}
+	data := &example.Object{Name: "data", Type: example.ObjectTypeDefault, DomainID: domain.GetID()}
+	admin := &example.Role{Name: "admin", DomainID: domain.GetID()}
+	member := &example.Role{Name: "member", DomainID: domain.GetID()}
+	return []caskin.Role{admin, member}, []caskin.Object{root, data}, []*caskin.Policy{
+		{Role: admin, Object: root, Domain: domain, Action: caskin.Write},
+		{Role: member, Object: root, Domain: domain, Action: caskin.Read},
+	}
+}
+
+func newStage(t *testing.T, option *caskin.Option) *stage {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := db.AutoMigrate(&example.User{}, &example.Role{}, &example.Object{}, &example.Domain{}); err != nil {
+		t.Fatal(err)
+	}
+	for i := 1; i <= 4; i++ {
+		if err := db.Create(&example.User{ID: uint64(i), Email: fmt.Sprintf("user%v@example.com", i), PhoneNumber: fmt.Sprint(i)}).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	e, err := casbin.NewEnforcer("configs/casbin_model.conf")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if option == nil {
+		option = &caskin.Option{}
+	}
+	if option.SuperAdminOption == nil {
+		option.SuperAdminOption = &caskin.SuperAdminOption{Enable: true, InitialUsers: []uint64{1}}
+	}
+	if option.DomainCreator == nil && len(option.DomainTemplates) == 0 {
+		option.DomainCreator = stageCreator
+	}
+
+	s := &stage{db: db, mdb: example.NewGormMDBByDB(db), e: e, domain: &example.Domain{Name: "domain_1"}}
+	m, err := caskin.New(s.mdb, e, example.NewEntryFactory(), option)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.executor = func(p caskin.CurrentUserProvider) interface{} {
+		return m.GetExecutor(p)
+	}
+
+	if err := s.executor(s.as(1)).(interface{ CreateDomain(caskin.Domain) error }).CreateDomain(s.domain); err != nil {
+		t.Fatal(err)
+	}
+	s.e.AddGroupingPolicy("role_1", "role_2", s.domain.Encode())
+	s.e.AddNamedGroupingPolicy("g2", "object_2", "object_1", s.domain.Encode())
+	return s
+}
+
+// as the user in the stage's domain
+func (s *stage) as(user uint64) caskin.CurrentUserProvider {
+	return &provider{user: &example.User{ID: user}, domain: s.domain}
+}
+
+// assign the user to the role in the stage's domain
+func (s *stage) assign(t *testing.T, user uint64, role uint64) {
+	u, r := &example.User{ID: user}, &example.Role{ID: role}
+	if _, err := s.e.AddRoleForUserInDomain(u.Encode(), r.Encode(), s.domain.Encode()); err != nil {
+		t.Fatal(err)
+	}
+}
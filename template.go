@@ -0,0 +1,125 @@
+package caskin
+
+import "reflect"
+
+// DomainMigration the report of migrating a domain to the latest DomainTemplate
+type DomainMigration struct {
+	Domain      Domain
+	FromVersion uint64
+	ToVersion   uint64
+	// the latest template's roles, objects and policies missing in the domain, to be added
+	Added *DomainSpec
+	// the old template's roles, objects and policies not in the latest template, to be removed
+	Removed *DomainSpec
+	// error of migrating the domain
+	Error error
+}
+
+// IsEmpty if there is nothing to migrate
+func (d *DomainMigration) IsEmpty() bool {
+	return d.FromVersion == d.ToVersion && isEmptySpec(d.Added) && isEmptySpec(d.Removed)
+}
+
+func isEmptySpec(spec *DomainSpec) bool {
+	return spec == nil || len(spec.Roles)+len(spec.Objects)+len(spec.Policies) == 0
+}
+
+// templateDrift get the latest template's entries missing in live domain,
+// and the old template's entries existing in live domain but not in the latest template.
+// the templates' entries are filled in id if they exist in live domain.
+func templateDrift(latest, old, live *DomainSpec) (added, removed *DomainSpec) {
+	added, removed = &DomainSpec{}, &DomainSpec{}
+	livePolicies := policyKeyMap(live.Policies)
+
+	for _, v := range latest.Roles {
+		if v.GetID() == 0 {
+			added.Roles = append(added.Roles, v)
+		}
+	}
+	for _, v := range latest.Objects {
+		if v.GetID() == 0 {
+			added.Objects = append(added.Objects, v)
+		}
+	}
+	for _, v := range latest.Policies {
+		if _, ok := livePolicies[toPolicyKey(v)]; !ok || v.Role.GetID() == 0 || v.Object.GetID() == 0 {
+			added.Policies = append(added.Policies, v)
+		}
+	}
+
+	latestRoles, latestObjects := getIDMap(latest.Roles), getIDMap(latest.Objects)
+	latestPolicies := policyKeyMap(latest.Policies)
+	for _, v := range old.Roles {
+		if _, ok := latestRoles[v.GetID()]; !ok && v.GetID() != 0 {
+			removed.Roles = append(removed.Roles, v)
+		}
+	}
+	for _, v := range old.Objects {
+		if _, ok := latestObjects[v.GetID()]; !ok && v.GetID() != 0 {
+			removed.Objects = append(removed.Objects, v)
+		}
+	}
+	for _, v := range old.Policies {
+		key := toPolicyKey(v)
+		_, ok1 := livePolicies[key]
+		_, ok2 := latestPolicies[key]
+		if ok1 && !ok2 {
+			removed.Policies = append(removed.Policies, v)
+		}
+	}
+
+	return
+}
+
+// DomainDrift the difference between the latest DomainTemplate and live domain
+type DomainDrift struct {
+	Domain  Domain
+	Version uint64
+	// template's roles, objects and policies which are not in the domain
+	Missing *DomainSpec
+	// template's roles and objects which are in the domain but modified
+	ModifiedRoles   []*RoleDrift
+	ModifiedObjects []*ObjectDrift
+	// the domain's roles, objects and policies which are not in template
+	Extra *DomainSpec
+}
+
+type RoleDrift struct {
+	Template Role
+	Live     Role
+}
+
+type ObjectDrift struct {
+	Template Object
+	Live     Object
+}
+
+// IsEmpty if the domain is the same as template
+func (d *DomainDrift) IsEmpty() bool {
+	return isEmptySpec(d.Missing) && isEmptySpec(d.Extra) && len(d.ModifiedRoles)+len(d.ModifiedObjects) == 0
+}
+
+// matchByKeyFields find the entry in candidates whose key fields are the same as one's,
+// it is nil if there is no key fields
+func matchByKeyFields(one entry, candidates []entry, keyFields []string) entry {
+	if len(keyFields) == 0 {
+		return nil
+	}
+
+	m := entryToMap(one)
+	for _, v := range candidates {
+		c := entryToMap(v)
+		matched := true
+		for _, k := range keyFields {
+			if !reflect.DeepEqual(m[k], c[k]) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return v
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,123 @@
+package caskin_test
+
+import (
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+type templateExecutor interface {
+	MigrateDomains(bool) ([]*caskin.DomainMigration, error)
+}
+
+// viewerCreator the template of version 1, it replaces member by viewer, and admin's object is root
+func viewerCreator(domain caskin.Domain) ([]caskin.Role, []caskin.Object, []*caskin.Policy) {
+	root := &example.Object{Name: "root", Type: example.ObjectTypeDefault, DomainID: domain.GetID()}
+	data := &example.Object{Name: "data", Type: example.ObjectTypeDefault, DomainID: domain.GetID()}
+	admin := &example.Role{Name: "admin", Object: "object_1", DomainID: domain.GetID()}
+	viewer := &example.Role{Name: "viewer", DomainID: domain.GetID()}
+	return []caskin.Role{admin, viewer}, []caskin.Object{root, data}, []*caskin.Policy{
+		{Role: admin, Object: root, Domain: domain, Action: caskin.Write},
+		{Role: viewer, Object: root, Domain: domain, Action: caskin.Read},
+	}
+}
+
+func TestMigrateDomains(t *testing.T) {
+	option := &caskin.Option{DomainCreator: stageCreator, TemplateKeyFields: []string{"name"}}
+	s := newStage(t, option)
+	option.DomainTemplates = []*caskin.DomainTemplate{{Version: 1, Creator: viewerCreator}}
+
+	executor := s.executor(s.as(1)).(templateExecutor)
+	migrations, err := executor.MigrateDomains(true)
+	if err != nil || len(migrations) != 1 {
+		t.Fatal(migrations, err)
+	}
+	m := migrations[0]
+	if m.Error != nil || m.FromVersion != 0 || m.ToVersion != 1 {
+		t.Fatal(m)
+	}
+	// admin is matched by name, member is from DomainCreator as version 0
+	if len(m.Added.Roles) != 1 || m.Added.Roles[0].(*example.Role).Name != "viewer" || len(m.Added.Objects) != 0 || len(m.Added.Policies) != 1 {
+		t.Fatal(m.Added)
+	}
+	if len(m.Removed.Roles) != 1 || m.Removed.Roles[0].GetID() != 2 || len(m.Removed.Objects) != 0 || len(m.Removed.Policies) != 1 {
+		t.Fatal(m.Removed)
+	}
+
+	if _, err := executor.MigrateDomains(false); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.mdb.TakeRole(&example.Role{ID: 2}); err == nil {
+		t.Fatal("member is not removed")
+	}
+	viewer := &example.Role{Name: "viewer", DomainID: s.domain.ID}
+	if err := s.mdb.TakeRole(viewer); err != nil {
+		t.Fatal(err)
+	}
+	if !s.e.HasPolicy(viewer.Encode(), s.domain.Encode(), "object_1", string(caskin.Read)) {
+		t.Fatal("viewer's policy is not added")
+	}
+}
+
+// editorCreator the template of version 2, it replaces viewer by editor
+func editorCreator(domain caskin.Domain) ([]caskin.Role, []caskin.Object, []*caskin.Policy) {
+	roles, objects, _ := viewerCreator(domain)
+	admin, root := roles[0], objects[0]
+	editor := &example.Role{Name: "editor", DomainID: domain.GetID()}
+	return []caskin.Role{admin, editor}, objects, []*caskin.Policy{
+		{Role: admin, Object: root, Domain: domain, Action: caskin.Write},
+		{Role: editor, Object: root, Domain: domain, Action: caskin.Write},
+	}
+}
+
+func TestMigrateDomainsTwice(t *testing.T) {
+	option := &caskin.Option{DomainCreator: stageCreator, TemplateKeyFields: []string{"name"}}
+	s := newStage(t, option)
+	option.DomainTemplates = []*caskin.DomainTemplate{{Version: 1, Creator: viewerCreator}}
+	executor := s.executor(s.as(1)).(templateExecutor)
+	if migrations, err := executor.MigrateDomains(false); err != nil || migrations[0].Error != nil {
+		t.Fatal(migrations, err)
+	}
+	if domain := (&example.Domain{ID: s.domain.ID}); s.mdb.TakeDomain(domain) != nil || domain.TemplateVersion != 1 {
+		t.Fatal("template version is not persisted", domain)
+	}
+
+	option.DomainTemplates = append(option.DomainTemplates, &caskin.DomainTemplate{Version: 2, Creator: editorCreator})
+	migrations, err := executor.MigrateDomains(false)
+	if err != nil || len(migrations) != 1 {
+		t.Fatal(migrations, err)
+	}
+	m := migrations[0]
+	if m.Error != nil || m.FromVersion != 1 || m.ToVersion != 2 {
+		t.Fatal(m)
+	}
+	// viewer is from version 1 then, so it is removed instead of being left as custom role
+	if len(m.Removed.Roles) != 1 || m.Removed.Roles[0].(*example.Role).Name != "viewer" {
+		t.Fatal(m.Removed)
+	}
+	if err := s.mdb.TakeRole(&example.Role{Name: "viewer", DomainID: s.domain.ID}); err == nil {
+		t.Fatal("viewer is not removed")
+	}
+	if err := s.mdb.TakeRole(&example.Role{Name: "editor", DomainID: s.domain.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	migrations, err = executor.MigrateDomains(true)
+	if err != nil || !migrations[0].IsEmpty() {
+		t.Fatal("domain is not up to date", migrations, err)
+	}
+}
+
+func TestGetDomainTemplate(t *testing.T) {
+	option := &caskin.Option{DomainCreator: stageCreator, DomainTemplates: []*caskin.DomainTemplate{{Version: 1, Creator: viewerCreator}}}
+	if v := option.GetDomainTemplate(0); v == nil || v.Creator == nil || v.Version != 0 {
+		t.Fatal(v)
+	}
+	if v := option.GetDomainTemplate(2); v != nil {
+		t.Fatal(v)
+	}
+	if v := option.GetLatestDomainTemplate(); v.Version != 1 {
+		t.Fatal(v)
+	}
+}
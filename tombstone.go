@@ -0,0 +1,118 @@
+package caskin
+
+import (
+	"sync"
+	"time"
+)
+
+// Tombstone the g, g2 and p rules removed by deleting an entry
+type Tombstone struct {
+	// code of the deleted entry
+	Code      string    `json:"code"`
+	Rules     []*Rule   `json:"rules"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// TombstoneStore the storage of tombstones, one tombstone per entry's code
+type TombstoneStore interface {
+	// save the tombstone, replace the old one of the same code
+	SaveTombstone(*Tombstone) error
+	// take the tombstone by code, it is ErrNotExists if there is none
+	TakeTombstone(string) (*Tombstone, error)
+	// delete the tombstone by code
+	DeleteTombstone(string) error
+	// delete tombstones deleted before the time
+	DeleteTombstoneBefore(time.Time) error
+}
+
+type memoryTombstoneStore struct {
+	mu         sync.Mutex
+	tombstones map[string]*Tombstone
+}
+
+// NewMemoryTombstoneStore the TombstoneStore in memory of current process,
+// tombstones are lost when the process exits and are not shared between processes,
+// and nothing expires them by itself, call PurgeTombstones to apply the retention
+func NewMemoryTombstoneStore() TombstoneStore {
+	return &memoryTombstoneStore{tombstones: map[string]*Tombstone{}}
+}
+
+func (m *memoryTombstoneStore) SaveTombstone(tombstone *Tombstone) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tombstones[tombstone.Code] = tombstone
+	return nil
+}
+
+func (m *memoryTombstoneStore) TakeTombstone(code string) (*Tombstone, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if tombstone, ok := m.tombstones[code]; ok {
+		return tombstone, nil
+	}
+	return nil, ErrNotExists
+}
+
+func (m *memoryTombstoneStore) DeleteTombstone(code string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.tombstones, code)
+	return nil
+}
+
+func (m *memoryTombstoneStore) DeleteTombstoneBefore(t time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k, v := range m.tombstones {
+		if v.DeletedAt.Before(t) {
+			delete(m.tombstones, k)
+		}
+	}
+	return nil
+}
+
+// userRules get user to roles 's g in rules
+func userRules(rules []*Rule, factory EntryFactory) []*Rule {
+	var out []*Rule
+	for _, v := range rules {
+		if v.PType != RolePType || len(v.Values) == 0 {
+			continue
+		}
+		if err := factory.NewUser().Decode(v.Values[0]); err == nil {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// roleRules get the role's g as user, parent or child, domain admin role's g and p in rules
+func roleRules(rules []*Rule, role Role) []*Rule {
+	var out []*Rule
+	code := role.Encode()
+	for _, v := range rules {
+		switch {
+		case v.PType == PolicyPType && len(v.Values) > 0 && v.Values[0] == code:
+		case v.PType == RolePType && len(v.Values) > 1 && (v.Values[0] == code || v.Values[1] == code):
+		default:
+			continue
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// objectRules get the object's g2 as child or parent and p in rules
+func objectRules(rules []*Rule, object Object) []*Rule {
+	var out []*Rule
+	code := object.Encode()
+	for _, v := range rules {
+		switch {
+		case v.PType == PolicyPType && len(v.Values) > 2 && v.Values[2] == code:
+		case v.PType == ObjectPType && len(v.Values) > 1 && (v.Values[0] == code || v.Values[1] == code):
+		default:
+			continue
+		}
+		out = append(out, v)
+	}
+	return out
+}
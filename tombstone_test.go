@@ -0,0 +1,153 @@
+package caskin_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+type tombstoneExecutor interface {
+	DeleteRole(caskin.Role, ...caskin.DeleteOption) error
+	RecoverRole(caskin.Role) error
+	DeleteDomain(caskin.Domain, ...caskin.DeleteOption) error
+	RecoverDomain(caskin.Domain) error
+	PurgeTombstones() error
+}
+
+func newTombstoneStage(t *testing.T) (*stage, caskin.TombstoneStore) {
+	store := caskin.NewMemoryTombstoneStore()
+	s := newStage(t, &caskin.Option{TombstoneOption: &caskin.TombstoneOption{
+		Store:            store,
+		Retention:        time.Hour,
+		RestoreOnRecover: true,
+	}})
+	// member is under root, then admin of user 3 has its write permission
+	if err := s.db.Model(&example.Role{ID: 2}).Update("object", "object_1").Error; err != nil {
+		t.Fatal(err)
+	}
+	s.assign(t, 2, 2)
+	s.assign(t, 3, 1)
+	return s, store
+}
+
+func TestRecoverRoleRestoreTombstone(t *testing.T) {
+	s, store := newTombstoneStage(t)
+	if err := s.executor(s.as(1)).(tombstoneExecutor).DeleteRole(&example.Role{ID: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := s.e.HasRoleForUser("user_2", "role_2", s.domain.Encode()); ok {
+		t.Fatal("rule of deleted role is kept")
+	}
+	if _, err := store.TakeTombstone("role_2"); err != nil {
+		t.Fatal(err)
+	}
+
+	// user 4 has no write permission of member
+	var perr *caskin.PermissionError
+	err := s.executor(s.as(4)).(tombstoneExecutor).RecoverRole(&example.Role{ID: 2, Object: "object_1"})
+	if !errors.As(err, &perr) {
+		t.Fatal(err)
+	}
+	// the object of the recovering role is taken from metadata database, not from the argument
+	s.e.AddPolicy("role_3", s.domain.Encode(), "object_9", string(caskin.Write))
+	s.e.AddGroupingPolicy("user_4", "role_3", s.domain.Encode())
+	err = s.executor(s.as(4)).(tombstoneExecutor).RecoverRole(&example.Role{ID: 2, Object: "object_9"})
+	if !errors.As(err, &perr) {
+		t.Fatal(err)
+	}
+	if _, err := store.TakeTombstone("role_2"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.executor(s.as(3)).(tombstoneExecutor).RecoverRole(&example.Role{ID: 2, Object: "object_1"}); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := s.e.HasRoleForUser("user_2", "role_2", s.domain.Encode()); !ok {
+		t.Fatal("rule of recovered role is not restored")
+	}
+	if !s.e.HasPolicy("role_2", s.domain.Encode(), "object_1", string(caskin.Read)) {
+		t.Fatal("policy of recovered role is not restored")
+	}
+	if _, err := store.TakeTombstone("role_2"); !errors.Is(err, caskin.ErrNotExists) {
+		t.Fatal(err)
+	}
+}
+
+func TestRecoverDomainRestoreTombstone(t *testing.T) {
+	s, store := newTombstoneStage(t)
+	if err := s.executor(s.as(1)).(tombstoneExecutor).DeleteDomain(s.domain); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := s.e.HasRoleForUser("user_3", "role_1", s.domain.Encode()); ok {
+		t.Fatal("rule of deleted domain is kept")
+	}
+
+	// only superadmin could restore all users of the domain
+	domain := &example.Domain{ID: s.domain.ID}
+	if err := s.executor(s.as(3)).(tombstoneExecutor).RecoverDomain(domain); !errors.Is(err, caskin.ErrIsNotSuperAdmin) {
+		t.Fatal(err)
+	}
+	if err := s.mdb.TakeDomain(&example.Domain{ID: s.domain.ID}); err == nil {
+		t.Fatal("domain is recovered without restoring its tombstone")
+	}
+
+	if err := s.executor(s.as(1)).(tombstoneExecutor).RecoverDomain(domain); err != nil {
+		t.Fatal(err)
+	}
+	if ok, _ := s.e.HasRoleForUser("user_3", "role_1", s.domain.Encode()); !ok {
+		t.Fatal("rule of recovered domain is not restored")
+	}
+	if _, err := store.TakeTombstone(s.domain.Encode()); !errors.Is(err, caskin.ErrNotExists) {
+		t.Fatal(err)
+	}
+}
+
+func TestPurgeTombstones(t *testing.T) {
+	s, store := newTombstoneStage(t)
+	if err := s.executor(s.as(1)).(tombstoneExecutor).DeleteRole(&example.Role{ID: 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := store.SaveTombstone(&caskin.Tombstone{Code: "role_9", DeletedAt: time.Now().Add(-2 * time.Hour)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.executor(s.as(3)).(tombstoneExecutor).PurgeTombstones(); !errors.Is(err, caskin.ErrIsNotSuperAdmin) {
+		t.Fatal(err)
+	}
+	if err := s.executor(s.as(1)).(tombstoneExecutor).PurgeTombstones(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.TakeTombstone("role_9"); !errors.Is(err, caskin.ErrNotExists) {
+		t.Fatal(err)
+	}
+	if _, err := store.TakeTombstone("role_2"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCreateRoleWithID(t *testing.T) {
+	s := newStage(t, nil)
+	s.assign(t, 3, 1)
+	var executor interface{} = s.executor(s.as(3))
+	creator := executor.(interface {
+		CreateRole(caskin.Role) error
+		RecoverRole(caskin.Role) error
+	})
+
+	// the caller-chosen id is neither live nor soft deleted, it is created instead of being taken from the trash
+	if err := creator.CreateRole(&example.Role{ID: 50, Name: "guest", Object: "object_1"}); err != nil {
+		t.Fatal(err)
+	}
+	if role := (&example.Role{ID: 50}); s.mdb.TakeRole(role) != nil || role.DomainID != s.domain.ID {
+		t.Fatal(role)
+	}
+	if err := creator.CreateRole(&example.Role{ID: 50, Name: "guest", Object: "object_1"}); !errors.Is(err, caskin.ErrAlreadyExists) {
+		t.Fatal(err)
+	}
+	if err := creator.RecoverRole(&example.Role{ID: 51, Object: "object_1"}); !errors.Is(err, caskin.ErrNotExists) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,100 @@
+package caskin_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+type trashExecutor interface {
+	DeleteRole(caskin.Role, ...caskin.DeleteOption) error
+	DeleteDomain(caskin.Domain, ...caskin.DeleteOption) error
+	ListDeletedRoles() ([]caskin.Role, error)
+	ListDeletedObjects() ([]caskin.Object, error)
+	ListDeletedDomains() ([]caskin.Domain, error)
+	Purge(caskin.EntryType, uint64) error
+}
+
+func TestListDeleted(t *testing.T) {
+	s := newStage(t, nil)
+	s.assign(t, 3, 1)
+	executor := s.executor(s.as(1)).(trashExecutor)
+	if err := executor.DeleteRole(&example.Role{ID: 2}); err != nil {
+		t.Fatal(err)
+	}
+
+	roles, err := executor.ListDeletedRoles()
+	if err != nil || len(roles) != 1 || roles[0].GetID() != 2 {
+		t.Fatal(roles, err)
+	}
+	if objects, err := executor.ListDeletedObjects(); err != nil || len(objects) != 0 {
+		t.Fatal(objects, err)
+	}
+	// the deleted member has no object, user 4 has no read permission of it
+	if roles, err := s.executor(s.as(4)).(trashExecutor).ListDeletedRoles(); err != nil || len(roles) != 0 {
+		t.Fatal(roles, err)
+	}
+
+	if err := executor.DeleteDomain(s.domain); err != nil {
+		t.Fatal(err)
+	}
+	if domains, err := executor.ListDeletedDomains(); err != nil || len(domains) != 1 {
+		t.Fatal(domains, err)
+	}
+	if _, err := s.executor(s.as(3)).(trashExecutor).ListDeletedDomains(); !errors.Is(err, caskin.ErrIsNotSuperAdmin) {
+		t.Fatal(err)
+	}
+}
+
+func TestPurgeDomainPermission(t *testing.T) {
+	s := newStage(t, nil)
+	s.assign(t, 3, 1)
+	if err := s.executor(s.as(1)).(trashExecutor).DeleteDomain(s.domain); err != nil {
+		t.Fatal(err)
+	}
+
+	// domain is not an object, only superadmin could purge it
+	if err := s.executor(s.as(3)).(trashExecutor).Purge(caskin.DomainEntry, s.domain.ID); !errors.Is(err, caskin.ErrIsNotSuperAdmin) {
+		t.Fatal(err)
+	}
+	if err := s.executor(s.as(1)).(trashExecutor).Purge(caskin.DomainEntry, s.domain.ID); err != nil {
+		t.Fatal(err)
+	}
+	var n int64
+	if err := s.db.Unscoped().Model(&example.Domain{}).Count(&n).Error; err != nil || n != 0 {
+		t.Fatal(n, err)
+	}
+}
+
+func TestTrashIsNotSupported(t *testing.T) {
+	s := newStage(t, nil)
+	// hide the TrashMetaDB API of the example's metadata database
+	mdb := struct{ caskin.MetaDB }{s.mdb}
+	m, err := caskin.New(mdb, s.e, example.NewEntryFactory(), &caskin.Option{
+		SuperAdminOption: &caskin.SuperAdminOption{Enable: true, InitialUsers: []uint64{1}},
+		DomainCreator:    stageCreator,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var executor interface{} = m.GetExecutor(s.as(1))
+	if _, err := executor.(trashExecutor).ListDeletedRoles(); !errors.Is(err, caskin.ErrTrashIsNotSupported) {
+		t.Fatal(err)
+	}
+	if err := executor.(trashExecutor).Purge(caskin.RoleEntry, 2); !errors.Is(err, caskin.ErrTrashIsNotSupported) {
+		t.Fatal(err)
+	}
+	if report, err := executor.(consistencyExecutor).VerifyConsistency(); err != nil || !report.IsEmpty() {
+		t.Fatal(report, err)
+	}
+	// without soft deleted entries, the rules kept to be recovered would be taken as orphan
+	if _, err := executor.(consistencyExecutor).RepairConsistency(false); !errors.Is(err, caskin.ErrTrashIsNotSupported) {
+		t.Fatal(err)
+	}
+	if caskin.GetErrorCode(caskin.ErrTrashIsNotSupported) != caskin.CodeTrashIsNotSupported {
+		t.Fatal(caskin.GetErrorCode(caskin.ErrTrashIsNotSupported))
+	}
+}
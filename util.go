@@ -1,15 +1,18 @@
 package caskin
 
 import (
+	"reflect"
+
 	"github.com/ahmetb/go-linq/v3"
 )
 
 // Filter filter source permission by u, d, action
 func Filter(e ienforcer, u User, d Domain, action Action, fn func() Object, source interface{}) interface{} {
+	out := reflect.New(reflect.TypeOf(source))
 	linq.From(source).Where(func(v interface{}) bool {
 		return Check(e, u, d, action, fn, v.(entry))
-	}).ToSlice(&source)
-	return source
+	}).ToSlice(out.Interface())
+	return out.Elem().Interface()
 }
 
 // Filter check entry permission by u, d, action
@@ -43,6 +46,15 @@ func isValid(e entry) error {
 	return nil
 }
 
+// isInDomain check the entry belongs to the domain
+func isInDomain(one inDomain, domain Domain) error {
+	if one.GetDomainID() != domain.GetID() {
+		return ErrCrossDomain
+	}
+
+	return nil
+}
+
 
 type Users []User
 
@@ -0,0 +1,109 @@
+package caskin
+
+import (
+	"fmt"
+	"math"
+)
+
+// entryCodecProbeID the id used to probe entry's Encode and Decode
+var entryCodecProbeID = []uint64{1, 42, math.MaxUint32, math.MaxUint64}
+
+// reservedCode the code used by caskin itself which no entry could use
+var reservedCode = []string{SuperadminRole, SuperadminDomain, DomainAdminRole}
+
+type entryCodec struct {
+	kind string
+	new  func() entry
+	// the reserved code this kind of entry is allowed to use
+	exempt string
+}
+
+// ValidateEntryFactory check the EntryFactory's Encode and Decode
+// 1. every kind of entry could decode what it encodes with the same id
+// 2. user, role, object, domain's codes are in disjoint namespaces
+// 3. no code collides with SuperadminRole, SuperadminDomain or DomainAdminRole,
+// except that role and domain could use SuperadminRole and SuperadminDomain
+// if superadmin is kept in metadata database by the option's RealSuperadminInDB
+func ValidateEntryFactory(factory EntryFactory, option *Option) error {
+	if factory == nil {
+		return ErrNil
+	}
+
+	codecs := []*entryCodec{
+		{kind: "user", new: func() entry { return factory.NewUser() }},
+		{kind: "role", new: func() entry { return factory.NewRole() }},
+		{kind: "object", new: func() entry { return factory.NewObject() }},
+		{kind: "domain", new: func() entry { return factory.NewDomain() }},
+	}
+	if option != nil && option.IsEnableSuperAdmin() && option.SuperAdminOption.RealSuperadminInDB {
+		codecs[1].exempt = SuperadminRole
+		codecs[3].exempt = SuperadminDomain
+	}
+
+	for _, c := range codecs {
+		if err := c.checkRoundTrip(); err != nil {
+			return err
+		}
+	}
+
+	for _, c := range codecs {
+		for _, id := range entryCodecProbeID {
+			code := c.encode(id)
+			for _, v := range reservedCode {
+				if code == v && code != c.exempt {
+					return fmt.Errorf("%w: %v code %q is reserved", ErrEntryCodecCollision, c.kind, code)
+				}
+			}
+			for _, other := range codecs {
+				if other != c && other.canDecode(code) {
+					return fmt.Errorf("%w: %v code %q is decoded as %v", ErrEntryCodecCollision, c.kind, code, other.kind)
+				}
+			}
+		}
+	}
+
+	for _, v := range reservedCode {
+		for _, c := range codecs {
+			if v != c.exempt && c.canDecode(v) {
+				return fmt.Errorf("%w: reserved code %q is decoded as %v", ErrEntryCodecCollision, v, c.kind)
+			}
+		}
+	}
+
+	return nil
+}
+
+func (c *entryCodec) checkRoundTrip() error {
+	for _, id := range entryCodecProbeID {
+		one := c.new()
+		if one == nil {
+			return fmt.Errorf("%w: factory provides nil %v", ErrInvalidEntryCodec, c.kind)
+		}
+
+		one.SetID(id)
+		code := one.Encode()
+		if code == "" {
+			return fmt.Errorf("%w: %v encode id %v to empty code", ErrInvalidEntryCodec, c.kind, id)
+		}
+
+		another := c.new()
+		if err := another.Decode(code); err != nil {
+			return fmt.Errorf("%w: %v decode %q failed: %v", ErrInvalidEntryCodec, c.kind, code, err)
+		}
+		if another.GetID() != id {
+			return fmt.Errorf("%w: %v encode id %v to %q but decode to id %v", ErrInvalidEntryCodec, c.kind, id, code, another.GetID())
+		}
+	}
+
+	return nil
+}
+
+func (c *entryCodec) encode(id uint64) string {
+	one := c.new()
+	one.SetID(id)
+	return one.Encode()
+}
+
+func (c *entryCodec) canDecode(code string) bool {
+	return c.new().Decode(code) == nil
+}
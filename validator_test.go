@@ -0,0 +1,78 @@
+package caskin_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/awatercolorpen/caskin"
+	"github.com/awatercolorpen/caskin/example"
+)
+
+type superadminRole struct {
+	example.Role
+}
+
+func (r *superadminRole) Encode() string {
+	if r.ID == caskin.DefaultSuperadminRoleID {
+		return caskin.SuperadminRole
+	}
+	return r.Role.Encode()
+}
+
+func (r *superadminRole) Decode(code string) error {
+	if code == caskin.SuperadminRole {
+		r.ID = caskin.DefaultSuperadminRoleID
+		return nil
+	}
+	return r.Role.Decode(code)
+}
+
+type superadminFactory struct {
+	caskin.EntryFactory
+}
+
+func (f *superadminFactory) NewRole() caskin.Role {
+	return &superadminRole{}
+}
+
+type collidingUser struct {
+	example.User
+}
+
+func (u *collidingUser) Encode() string {
+	return fmt.Sprintf("object_%v", u.ID)
+}
+
+func (u *collidingUser) Decode(code string) error {
+	_, err := fmt.Sscanf(code, "object_%v", &u.ID)
+	return err
+}
+
+type collidingFactory struct {
+	caskin.EntryFactory
+}
+
+func (f *collidingFactory) NewUser() caskin.User {
+	return &collidingUser{}
+}
+
+func TestValidateEntryFactory(t *testing.T) {
+	if err := caskin.ValidateEntryFactory(example.NewEntryFactory(), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	err := caskin.ValidateEntryFactory(&collidingFactory{example.NewEntryFactory()}, nil)
+	if !errors.Is(err, caskin.ErrEntryCodecCollision) {
+		t.Fatal(err)
+	}
+
+	factory := &superadminFactory{example.NewEntryFactory()}
+	if err := caskin.ValidateEntryFactory(factory, nil); !errors.Is(err, caskin.ErrEntryCodecCollision) {
+		t.Fatal(err)
+	}
+	option := &caskin.Option{SuperAdminOption: &caskin.SuperAdminOption{Enable: true, RealSuperadminInDB: true}}
+	if err := caskin.ValidateEntryFactory(factory, option); err != nil {
+		t.Fatal(err)
+	}
+}